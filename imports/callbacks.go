@@ -2,21 +2,123 @@ package imports
 
 import (
 	"context"
+	"io"
+	"sync"
+
 	"github.com/tetratelabs/wazero/api"
 )
 
+// callbackKey identifies a callback struct in the linear memory of a module.
+// The host module is shared by every module in the runtime, so the pointer
+// alone is not unique.
+type callbackKey struct {
+	mod     api.Module
+	pointer uint32
+}
+
+var (
+	fileReadersLock sync.RWMutex
+	fileReaders     = map[callbackKey]io.ReaderAt{}
+
+	fileWritersLock sync.RWMutex
+	fileWriters     = map[callbackKey]io.Writer{}
+)
+
+// RegisterFileReader registers the reader that serves the blocks of the
+// FPDF_FILEACCESS struct at param in the linear memory of mod.
+func RegisterFileReader(mod api.Module, param uint32, reader io.ReaderAt) {
+	fileReadersLock.Lock()
+	defer fileReadersLock.Unlock()
+	fileReaders[callbackKey{mod: mod, pointer: param}] = reader
+}
+
+// UnregisterFileReader removes the reader registered by RegisterFileReader.
+func UnregisterFileReader(mod api.Module, param uint32) {
+	fileReadersLock.Lock()
+	defer fileReadersLock.Unlock()
+	delete(fileReaders, callbackKey{mod: mod, pointer: param})
+}
+
+// RegisterFileWriter registers the writer that receives the blocks of the
+// FPDF_FILEWRITE struct at pThis in the linear memory of mod.
+func RegisterFileWriter(mod api.Module, pThis uint32, writer io.Writer) {
+	fileWritersLock.Lock()
+	defer fileWritersLock.Unlock()
+	fileWriters[callbackKey{mod: mod, pointer: pThis}] = writer
+}
+
+// UnregisterFileWriter removes the writer registered by RegisterFileWriter.
+func UnregisterFileWriter(mod api.Module, pThis uint32) {
+	fileWritersLock.Lock()
+	defer fileWritersLock.Unlock()
+	delete(fileWriters, callbackKey{mod: mod, pointer: pThis})
+}
+
 type FPDF_FILEACCESS_CB struct {
 }
 
+// Call reads size bytes at position from the reader registered for param
+// into pBuf. It returns 1 on success and 0 on failure.
 func (cb FPDF_FILEACCESS_CB) Call(ctx context.Context, mod api.Module, stack []uint64) {
-	stack[0] = uint64(0)
+	param := api.DecodeU32(stack[0])
+	position := api.DecodeU32(stack[1])
+	pBuf := api.DecodeU32(stack[2])
+	size := api.DecodeU32(stack[3])
+
+	fileReadersLock.RLock()
+	reader, ok := fileReaders[callbackKey{mod: mod, pointer: param}]
+	fileReadersLock.RUnlock()
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	// Read straight into linear memory, this is a view and not a copy.
+	buf, ok := mod.Memory().Read(ctx, pBuf, size)
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	n, err := reader.ReadAt(buf, int64(position))
+	if n != len(buf) || (err != nil && err != io.EOF) {
+		stack[0] = uint64(0)
+		return
+	}
+
+	stack[0] = uint64(1)
 	return
 }
 
 type FPDF_FILEWRITE_CB struct {
 }
 
+// Call writes the size bytes at pData to the writer registered for pThis. It
+// returns 1 on success and 0 on failure.
 func (cb FPDF_FILEWRITE_CB) Call(ctx context.Context, mod api.Module, stack []uint64) {
-	stack[0] = uint64(0)
+	pThis := api.DecodeU32(stack[0])
+	pData := api.DecodeU32(stack[1])
+	size := api.DecodeU32(stack[2])
+
+	fileWritersLock.RLock()
+	writer, ok := fileWriters[callbackKey{mod: mod, pointer: pThis}]
+	fileWritersLock.RUnlock()
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	data, ok := mod.Memory().Read(ctx, pData, size)
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		stack[0] = uint64(0)
+		return
+	}
+
+	stack[0] = uint64(1)
 	return
 }
@@ -0,0 +1,161 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// memoryModule is a wasm module that only exports one page of memory.
+var memoryModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	0x07, 0x0a, 0x01, 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+}
+
+// newTestModule instantiates memoryModule, for calling the host functions
+// directly.
+func newTestModule(t *testing.T) api.Module {
+	t.Helper()
+
+	ctx := context.Background()
+	r := wazero.NewRuntime(ctx)
+	t.Cleanup(func() { r.Close(ctx) })
+
+	mod, err := r.InstantiateModuleFromBinary(ctx, memoryModule)
+	if err != nil {
+		t.Fatalf("could not instantiate module: %v", err)
+	}
+
+	return mod
+}
+
+// failingReaderAt fails every read.
+type failingReaderAt struct{}
+
+func (failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestFileAccessCallback(t *testing.T) {
+	const param, buffer = 16, 64
+
+	file := strings.NewReader("hello world")
+
+	tests := []struct {
+		name     string
+		position uint32
+		size     uint32
+		reader   io.ReaderAt
+		want     uint64
+		wantData string
+	}{
+		{name: "start", position: 0, size: 5, reader: file, want: 1, wantData: "hello"},
+		{name: "middle", position: 6, size: 5, reader: file, want: 1, wantData: "world"},
+		{name: "until the end", position: 10, size: 1, reader: file, want: 1, wantData: "d"},
+		{name: "past the end", position: 8, size: 5, reader: file, want: 0},
+		{name: "outside of memory", position: 0, size: 0x10000, reader: file, want: 0},
+		{name: "failing reader", position: 0, size: 5, reader: failingReaderAt{}, want: 0},
+		{name: "unregistered", position: 0, size: 5, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mod := newTestModule(t)
+
+			if test.reader != nil {
+				RegisterFileReader(mod, param, test.reader)
+				defer UnregisterFileReader(mod, param)
+			}
+
+			stack := []uint64{param, uint64(test.position), buffer, uint64(test.size)}
+			FPDF_FILEACCESS_CB{}.Call(ctx, mod, stack)
+			if stack[0] != test.want {
+				t.Fatalf("FPDF_FILEACCESS_CB returned %d, want %d", stack[0], test.want)
+			}
+
+			if test.want == 0 {
+				return
+			}
+
+			data, _ := mod.Memory().Read(ctx, buffer, test.size)
+			if string(data) != test.wantData {
+				t.Errorf("FPDF_FILEACCESS_CB read %q, want %q", data, test.wantData)
+			}
+		})
+	}
+}
+
+func TestFileAccessCallbackPerModule(t *testing.T) {
+	ctx := context.Background()
+	first, second := newTestModule(t), newTestModule(t)
+
+	RegisterFileReader(first, 16, strings.NewReader("first"))
+	defer UnregisterFileReader(first, 16)
+
+	stack := []uint64{16, 0, 64, 5}
+	FPDF_FILEACCESS_CB{}.Call(ctx, second, stack)
+	if stack[0] != 0 {
+		t.Errorf("FPDF_FILEACCESS_CB used the reader of another module")
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestFileWriteCallback(t *testing.T) {
+	ctx := context.Background()
+	mod := newTestModule(t)
+
+	const pThis, data = 16, 64
+	mod.Memory().Write(ctx, data, []byte("hello world"))
+
+	var written bytes.Buffer
+	RegisterFileWriter(mod, pThis, &written)
+
+	for _, block := range [][2]uint32{{data, 5}, {data + 5, 6}} {
+		stack := []uint64{pThis, uint64(block[0]), uint64(block[1])}
+		FPDF_FILEWRITE_CB{}.Call(ctx, mod, stack)
+		if stack[0] != 1 {
+			t.Fatalf("FPDF_FILEWRITE_CB returned %d, want 1", stack[0])
+		}
+	}
+
+	if written.String() != "hello world" {
+		t.Errorf("FPDF_FILEWRITE_CB wrote %q, want %q", written.String(), "hello world")
+	}
+
+	stack := []uint64{pThis, data, 0x10000}
+	FPDF_FILEWRITE_CB{}.Call(ctx, mod, stack)
+	if stack[0] != 0 {
+		t.Errorf("FPDF_FILEWRITE_CB outside of memory returned %d, want 0", stack[0])
+	}
+
+	UnregisterFileWriter(mod, pThis)
+
+	stack = []uint64{pThis, data, 5}
+	FPDF_FILEWRITE_CB{}.Call(ctx, mod, stack)
+	if stack[0] != 0 {
+		t.Errorf("FPDF_FILEWRITE_CB after unregistering returned %d, want 0", stack[0])
+	}
+
+	RegisterFileWriter(mod, pThis, failingWriter{})
+	defer UnregisterFileWriter(mod, pThis)
+
+	stack = []uint64{pThis, data, 5}
+	FPDF_FILEWRITE_CB{}.Call(ctx, mod, stack)
+	if stack[0] != 0 {
+		t.Errorf("FPDF_FILEWRITE_CB with a failing writer returned %d, want 0", stack[0])
+	}
+}
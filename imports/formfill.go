@@ -0,0 +1,260 @@
+package imports
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// FormFillHandler handles the callbacks of an FPDF_FORMFILLINFO struct. The
+// handles are pointers in the linear memory of the module that made the call.
+type FormFillHandler interface {
+	// Invalidate is called when an area of a page needs to be repainted.
+	Invalidate(ctx context.Context, page uint32, left, top, right, bottom float64)
+
+	// OutputSelectedRect is called when text is selected in a form field.
+	OutputSelectedRect(ctx context.Context, page uint32, left, top, right, bottom float64)
+
+	// SetCursor is called when the cursor should change.
+	SetCursor(ctx context.Context, cursorType int32)
+
+	// SetTimer is called to install a timer, it returns the timer ID or 0 when
+	// timers are not supported.
+	SetTimer(ctx context.Context, elapse int32, timerFunc uint32) int32
+
+	// KillTimer is called to remove a timer installed by SetTimer.
+	KillTimer(ctx context.Context, timerID int32)
+
+	// OnChange is called when the content of a form field changes.
+	OnChange(ctx context.Context)
+
+	// GetPage returns the loaded page at pageIndex of document, or 0.
+	GetPage(ctx context.Context, document uint32, pageIndex int32) uint32
+
+	// GetCurrentPage returns the page that is currently shown, or 0.
+	GetCurrentPage(ctx context.Context, document uint32) uint32
+
+	// GetRotation returns the rotation of page in quarter turns.
+	GetRotation(ctx context.Context, page uint32) int32
+
+	// ExecuteNamedAction is called for a named action, like NextPage.
+	ExecuteNamedAction(ctx context.Context, namedAction string)
+
+	// SetTextFieldFocus is called when a text field gains or loses focus.
+	SetTextFieldFocus(ctx context.Context, value string, isFocus bool)
+
+	// DoURIAction is called when a URI action is triggered.
+	DoURIAction(ctx context.Context, uri string)
+
+	// DoGoToAction is called when a go to action is triggered.
+	DoGoToAction(ctx context.Context, pageIndex int32, zoomMode int32, position []float32)
+}
+
+var (
+	formFillHandlersLock sync.RWMutex
+	formFillHandlers     = map[callbackKey]FormFillHandler{}
+)
+
+// RegisterFormFillHandler registers the handler for the callbacks of the
+// FPDF_FORMFILLINFO struct at pThis in the linear memory of mod.
+func RegisterFormFillHandler(mod api.Module, pThis uint32, handler FormFillHandler) {
+	formFillHandlersLock.Lock()
+	defer formFillHandlersLock.Unlock()
+	formFillHandlers[callbackKey{mod: mod, pointer: pThis}] = handler
+}
+
+// UnregisterFormFillHandler removes the handler registered by
+// RegisterFormFillHandler.
+func UnregisterFormFillHandler(mod api.Module, pThis uint32) {
+	formFillHandlersLock.Lock()
+	defer formFillHandlersLock.Unlock()
+	delete(formFillHandlers, callbackKey{mod: mod, pointer: pThis})
+}
+
+func formFillHandler(mod api.Module, pThis uint64) (FormFillHandler, bool) {
+	formFillHandlersLock.RLock()
+	defer formFillHandlersLock.RUnlock()
+	handler, ok := formFillHandlers[callbackKey{mod: mod, pointer: api.DecodeU32(pThis)}]
+	return handler, ok
+}
+
+// readCString reads a NUL terminated string at pointer.
+func readCString(ctx context.Context, mod api.Module, pointer uint32) string {
+	if pointer == 0 {
+		return ""
+	}
+
+	var s []byte
+	for {
+		b, ok := mod.Memory().ReadByte(ctx, pointer+uint32(len(s)))
+		if !ok || b == 0 {
+			return string(s)
+		}
+		s = append(s, b)
+	}
+}
+
+// readWideString reads length UTF-16LE code units at pointer.
+func readWideString(ctx context.Context, mod api.Module, pointer, length uint32) string {
+	data, ok := mod.Memory().Read(ctx, pointer, length*2)
+	if !ok {
+		return ""
+	}
+
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	return string(utf16.Decode(units))
+}
+
+type FPDF_FORMFILLINFO_FFI_Invalidate struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_Invalidate) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.Invalidate(ctx, api.DecodeU32(stack[1]), api.DecodeF64(stack[2]), api.DecodeF64(stack[3]), api.DecodeF64(stack[4]), api.DecodeF64(stack[5]))
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_OutputSelectedRect struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_OutputSelectedRect) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.OutputSelectedRect(ctx, api.DecodeU32(stack[1]), api.DecodeF64(stack[2]), api.DecodeF64(stack[3]), api.DecodeF64(stack[4]), api.DecodeF64(stack[5]))
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_SetCursor struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_SetCursor) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.SetCursor(ctx, api.DecodeI32(stack[1]))
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_SetTimer struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_SetTimer) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	handler, ok := formFillHandler(mod, stack[0])
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	stack[0] = api.EncodeI32(handler.SetTimer(ctx, api.DecodeI32(stack[1]), api.DecodeU32(stack[2])))
+}
+
+type FPDF_FORMFILLINFO_FFI_KillTimer struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_KillTimer) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.KillTimer(ctx, api.DecodeI32(stack[1]))
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_OnChange struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_OnChange) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.OnChange(ctx)
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_GetPage struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_GetPage) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	handler, ok := formFillHandler(mod, stack[0])
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	stack[0] = api.EncodeU32(handler.GetPage(ctx, api.DecodeU32(stack[1]), api.DecodeI32(stack[2])))
+}
+
+type FPDF_FORMFILLINFO_FFI_GetCurrentPage struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_GetCurrentPage) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	handler, ok := formFillHandler(mod, stack[0])
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	stack[0] = api.EncodeU32(handler.GetCurrentPage(ctx, api.DecodeU32(stack[1])))
+}
+
+type FPDF_FORMFILLINFO_FFI_GetRotation struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_GetRotation) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	handler, ok := formFillHandler(mod, stack[0])
+	if !ok {
+		stack[0] = uint64(0)
+		return
+	}
+
+	stack[0] = api.EncodeI32(handler.GetRotation(ctx, api.DecodeU32(stack[1])))
+}
+
+type FPDF_FORMFILLINFO_FFI_ExecuteNamedAction struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_ExecuteNamedAction) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.ExecuteNamedAction(ctx, readCString(ctx, mod, api.DecodeU32(stack[1])))
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_SetTextFieldFocus struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_SetTextFieldFocus) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.SetTextFieldFocus(ctx, readWideString(ctx, mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2])), api.DecodeI32(stack[3]) != 0)
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_DoURIAction struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_DoURIAction) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	if handler, ok := formFillHandler(mod, stack[0]); ok {
+		handler.DoURIAction(ctx, readCString(ctx, mod, api.DecodeU32(stack[1])))
+	}
+}
+
+type FPDF_FORMFILLINFO_FFI_DoGoToAction struct {
+}
+
+func (cb FPDF_FORMFILLINFO_FFI_DoGoToAction) Call(ctx context.Context, mod api.Module, stack []uint64) {
+	handler, ok := formFillHandler(mod, stack[0])
+	if !ok {
+		return
+	}
+
+	positionPointer := api.DecodeU32(stack[3])
+	count := api.DecodeI32(stack[4])
+	if positionPointer == 0 || count < 0 {
+		count = 0
+	}
+
+	position := make([]float32, count)
+	for i := range position {
+		position[i], _ = mod.Memory().ReadFloat32Le(ctx, positionPointer+uint32(i*4))
+	}
+
+	handler.DoGoToAction(ctx, api.DecodeI32(stack[1]), api.DecodeI32(stack[2]), position)
+}
@@ -0,0 +1,254 @@
+package imports
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// recordingHandler records the calls of the form fill callbacks.
+type recordingHandler struct {
+	calls []string
+	args  [][]interface{}
+}
+
+func (h *recordingHandler) record(name string, args ...interface{}) {
+	h.calls = append(h.calls, name)
+	h.args = append(h.args, args)
+}
+
+func (h *recordingHandler) Invalidate(ctx context.Context, page uint32, left, top, right, bottom float64) {
+	h.record("Invalidate", page, left, top, right, bottom)
+}
+
+func (h *recordingHandler) OutputSelectedRect(ctx context.Context, page uint32, left, top, right, bottom float64) {
+	h.record("OutputSelectedRect", page, left, top, right, bottom)
+}
+
+func (h *recordingHandler) SetCursor(ctx context.Context, cursorType int32) {
+	h.record("SetCursor", cursorType)
+}
+
+func (h *recordingHandler) SetTimer(ctx context.Context, elapse int32, timerFunc uint32) int32 {
+	h.record("SetTimer", elapse, timerFunc)
+	return 7
+}
+
+func (h *recordingHandler) KillTimer(ctx context.Context, timerID int32) {
+	h.record("KillTimer", timerID)
+}
+
+func (h *recordingHandler) OnChange(ctx context.Context) {
+	h.record("OnChange")
+}
+
+func (h *recordingHandler) GetPage(ctx context.Context, document uint32, pageIndex int32) uint32 {
+	h.record("GetPage", document, pageIndex)
+	return 300
+}
+
+func (h *recordingHandler) GetCurrentPage(ctx context.Context, document uint32) uint32 {
+	h.record("GetCurrentPage", document)
+	return 400
+}
+
+func (h *recordingHandler) GetRotation(ctx context.Context, page uint32) int32 {
+	h.record("GetRotation", page)
+	return 1
+}
+
+func (h *recordingHandler) ExecuteNamedAction(ctx context.Context, namedAction string) {
+	h.record("ExecuteNamedAction", namedAction)
+}
+
+func (h *recordingHandler) SetTextFieldFocus(ctx context.Context, value string, isFocus bool) {
+	h.record("SetTextFieldFocus", value, isFocus)
+}
+
+func (h *recordingHandler) DoURIAction(ctx context.Context, uri string) {
+	h.record("DoURIAction", uri)
+}
+
+func (h *recordingHandler) DoGoToAction(ctx context.Context, pageIndex int32, zoomMode int32, position []float32) {
+	h.record("DoGoToAction", pageIndex, zoomMode, position)
+}
+
+func TestReadCString(t *testing.T) {
+	ctx := context.Background()
+	mod := newTestModule(t)
+	mod.Memory().Write(ctx, 100, []byte("NextPage\x00junk"))
+	mod.Memory().Write(ctx, 0xfffc, []byte("end"))
+
+	tests := []struct {
+		name    string
+		pointer uint32
+		want    string
+	}{
+		{name: "string", pointer: 100, want: "NextPage"},
+		{name: "empty", pointer: 108, want: ""},
+		{name: "null", pointer: 0, want: ""},
+		{name: "until the end of memory", pointer: 0xfffc, want: "end"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := readCString(ctx, mod, test.pointer); got != test.want {
+				t.Errorf("readCString(%d) = %q, want %q", test.pointer, got, test.want)
+			}
+		})
+	}
+}
+
+func TestReadWideString(t *testing.T) {
+	ctx := context.Background()
+	mod := newTestModule(t)
+	mod.Memory().Write(ctx, 100, []byte{'h', 0, 0xe9, 0, 0x34, 0xd8, 0x1e, 0xdd})
+
+	tests := []struct {
+		name    string
+		pointer uint32
+		length  uint32
+		want    string
+	}{
+		{name: "string", pointer: 100, length: 4, want: "hé𝄞"},
+		{name: "prefix", pointer: 100, length: 1, want: "h"},
+		{name: "empty", pointer: 100, length: 0, want: ""},
+		{name: "outside of memory", pointer: 0xfffe, length: 2, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := readWideString(ctx, mod, test.pointer, test.length); got != test.want {
+				t.Errorf("readWideString(%d, %d) = %q, want %q", test.pointer, test.length, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormFillCallbacks(t *testing.T) {
+	ctx := context.Background()
+	mod := newTestModule(t)
+
+	const pThis = 16
+	mod.Memory().Write(ctx, 100, []byte("https://example.com\x00"))
+	mod.Memory().Write(ctx, 200, []byte{'o', 0, 'k', 0})
+
+	position := make([]byte, 8)
+	binary.LittleEndian.PutUint32(position, math.Float32bits(1.5))
+	binary.LittleEndian.PutUint32(position[4:], math.Float32bits(-2))
+	mod.Memory().Write(ctx, 300, position)
+
+	handler := &recordingHandler{}
+	RegisterFormFillHandler(mod, pThis, handler)
+	defer UnregisterFormFillHandler(mod, pThis)
+
+	tests := []struct {
+		callback api.GoModuleFunction
+		stack    []uint64
+		result   uint64
+		call     string
+		args     []interface{}
+	}{
+		{
+			callback: FPDF_FORMFILLINFO_FFI_Invalidate{},
+			stack:    []uint64{pThis, 500, api.EncodeF64(1), api.EncodeF64(2), api.EncodeF64(3), api.EncodeF64(4)},
+			call:     "Invalidate",
+			args:     []interface{}{uint32(500), 1.0, 2.0, 3.0, 4.0},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_SetCursor{},
+			stack:    []uint64{pThis, 2},
+			call:     "SetCursor",
+			args:     []interface{}{int32(2)},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_SetTimer{},
+			stack:    []uint64{pThis, 250, 42},
+			result:   7,
+			call:     "SetTimer",
+			args:     []interface{}{int32(250), uint32(42)},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_GetPage{},
+			stack:    []uint64{pThis, 600, 3},
+			result:   300,
+			call:     "GetPage",
+			args:     []interface{}{uint32(600), int32(3)},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_GetRotation{},
+			stack:    []uint64{pThis, 500},
+			result:   1,
+			call:     "GetRotation",
+			args:     []interface{}{uint32(500)},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_SetTextFieldFocus{},
+			stack:    []uint64{pThis, 200, 2, 1},
+			call:     "SetTextFieldFocus",
+			args:     []interface{}{"ok", true},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_DoURIAction{},
+			stack:    []uint64{pThis, 100},
+			call:     "DoURIAction",
+			args:     []interface{}{"https://example.com"},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_DoGoToAction{},
+			stack:    []uint64{pThis, 4, 1, 300, 2},
+			call:     "DoGoToAction",
+			args:     []interface{}{int32(4), int32(1), []float32{1.5, -2}},
+		},
+		{
+			callback: FPDF_FORMFILLINFO_FFI_DoGoToAction{},
+			stack:    []uint64{pThis, 4, 1, 0, 2},
+			call:     "DoGoToAction",
+			args:     []interface{}{int32(4), int32(1), []float32{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.call, func(t *testing.T) {
+			handler.calls, handler.args = nil, nil
+
+			test.callback.Call(ctx, mod, test.stack)
+
+			if !reflect.DeepEqual(handler.calls, []string{test.call}) {
+				t.Fatalf("calls = %v, want %s", handler.calls, test.call)
+			}
+
+			if !reflect.DeepEqual(handler.args[0], test.args) {
+				t.Errorf("%s args = %v, want %v", test.call, handler.args[0], test.args)
+			}
+
+			if test.result != 0 && test.stack[0] != test.result {
+				t.Errorf("%s returned %d, want %d", test.call, test.stack[0], test.result)
+			}
+		})
+	}
+}
+
+func TestFormFillCallbacksUnregistered(t *testing.T) {
+	ctx := context.Background()
+	mod := newTestModule(t)
+
+	handler := &recordingHandler{}
+	RegisterFormFillHandler(mod, 16, handler)
+	defer UnregisterFormFillHandler(mod, 16)
+
+	stack := []uint64{32, 250, 42}
+	FPDF_FORMFILLINFO_FFI_SetTimer{}.Call(ctx, mod, stack)
+	if stack[0] != 0 {
+		t.Errorf("SetTimer without a handler returned %d, want 0", stack[0])
+	}
+
+	FPDF_FORMFILLINFO_FFI_OnChange{}.Call(ctx, mod, []uint64{32})
+	if len(handler.calls) != 0 {
+		t.Errorf("callbacks without a handler called %v", handler.calls)
+	}
+}
@@ -41,4 +41,17 @@ type functionExporter struct{}
 func (e *functionExporter) ExportFunctions(b wazero.HostModuleBuilder) {
 	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FILEACCESS_CB{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("FPDF_FILEACCESS_CB")
 	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FILEWRITE_CB{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("FPDF_FILEWRITE_CB")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_Invalidate{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeF64, api.ValueTypeF64, api.ValueTypeF64, api.ValueTypeF64}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_Invalidate")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_OutputSelectedRect{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeF64, api.ValueTypeF64, api.ValueTypeF64, api.ValueTypeF64}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_OutputSelectedRect")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_SetCursor{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_SetCursor")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_SetTimer{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("FPDF_FORMFILLINFO_FFI_SetTimer")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_KillTimer{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_KillTimer")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_OnChange{}, []api.ValueType{api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_OnChange")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_GetPage{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("FPDF_FORMFILLINFO_FFI_GetPage")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_GetCurrentPage{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("FPDF_FORMFILLINFO_FFI_GetCurrentPage")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_GetRotation{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("FPDF_FORMFILLINFO_FFI_GetRotation")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_ExecuteNamedAction{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_ExecuteNamedAction")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_SetTextFieldFocus{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_SetTextFieldFocus")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_DoURIAction{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_DoURIAction")
+	b.NewFunctionBuilder().WithGoModuleFunction(FPDF_FORMFILLINFO_FFI_DoGoToAction{}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).Export("FPDF_FORMFILLINFO_FFI_DoGoToAction")
 }
@@ -0,0 +1,138 @@
+package pdfium
+
+import "context"
+
+// ActionType is the type of an action.
+type ActionType int
+
+const (
+	ActionTypeUnsupported  ActionType = 0 // PDFACTION_UNSUPPORTED
+	ActionTypeGoTo         ActionType = 1 // PDFACTION_GOTO, go to a destination in the document.
+	ActionTypeRemoteGoTo   ActionType = 2 // PDFACTION_REMOTEGOTO, go to a destination in another file.
+	ActionTypeURI          ActionType = 3 // PDFACTION_URI, open a URI.
+	ActionTypeLaunch       ActionType = 4 // PDFACTION_LAUNCH, launch an application or open a file.
+	ActionTypeEmbeddedGoTo ActionType = 5 // PDFACTION_EMBEDDEDGOTO, go to a destination in an embedded file.
+)
+
+// Destination is a location in a document.
+type Destination struct {
+	// PageIndex is the index of the target page.
+	PageIndex int
+
+	// X and Y are the target location on the page and Zoom the target zoom
+	// factor, they are only set when HasX, HasY and HasZoom are true.
+	X       float64
+	Y       float64
+	Zoom    float64
+	HasX    bool
+	HasY    bool
+	HasZoom bool
+}
+
+// destination reads the FPDF_DEST handle dest. It returns nil when the
+// destination does not point to a page of the document.
+func (d *Document) destination(ctx context.Context, dest uint64) (*Destination, error) {
+	i := d.instance
+
+	pageIndex, err := i.call1(ctx, "FPDFDest_GetDestPageIndex", d.handle, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	if int32(pageIndex) < 0 {
+		return nil, nil
+	}
+
+	destination := &Destination{
+		PageIndex: int(int32(pageIndex)),
+	}
+
+	// Three FPDF_BOOLs followed by three FS_FLOATs, all 4 bytes.
+	pointer, err := i.alloc(ctx, 24)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, "FPDFDest_GetLocationInPage", dest, pointer, pointer+4, pointer+8, pointer+12, pointer+16, pointer+20)
+	if err != nil {
+		return nil, err
+	}
+
+	if success == 0 {
+		return destination, nil
+	}
+
+	has, err := i.readInt32s(ctx, pointer, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := i.readFloat32s(ctx, pointer+12, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	destination.HasX, destination.HasY, destination.HasZoom = has[0] != 0, has[1] != 0, has[2] != 0
+	destination.X, destination.Y, destination.Zoom = float64(location[0]), float64(location[1]), float64(location[2])
+
+	return destination, nil
+}
+
+// actionType returns the type of the FPDF_ACTION handle action.
+func (d *Document) actionType(ctx context.Context, action uint64) (ActionType, error) {
+	actionType, err := d.instance.call1(ctx, "FPDFAction_GetType", action)
+	if err != nil {
+		return 0, err
+	}
+
+	return ActionType(uint32(actionType)), nil
+}
+
+// actionDestination returns the destination of the go to action action, or
+// nil when it has none.
+func (d *Document) actionDestination(ctx context.Context, action uint64) (*Destination, error) {
+	dest, err := d.instance.call1(ctx, "FPDFAction_GetDest", d.handle, action)
+	if err != nil {
+		return nil, err
+	}
+
+	if dest == 0 {
+		return nil, nil
+	}
+
+	return d.destination(ctx, dest)
+}
+
+// actionURI returns the URI of the URI action action.
+func (d *Document) actionURI(ctx context.Context, action uint64) (string, error) {
+	i := d.instance
+
+	// The URI is a 7-bit ASCII string, the length includes the terminating
+	// NUL.
+	length, err := i.call1(ctx, "FPDFAction_GetURIPath", d.handle, action, 0, 0)
+	if err != nil {
+		return "", err
+	}
+
+	if uint32(length) <= 1 {
+		return "", nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, length)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, "FPDFAction_GetURIPath", d.handle, action, bufferPointer, length); err != nil {
+		return "", err
+	}
+
+	uri, err := i.view(ctx, bufferPointer, length-1)
+	if err != nil {
+		return "", err
+	}
+
+	return string(uri), nil
+}
@@ -0,0 +1,543 @@
+package pdfium
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// AnnotationSubtype is the subtype of an annotation.
+type AnnotationSubtype int
+
+const (
+	AnnotationSubtypeUnknown        AnnotationSubtype = 0  // FPDF_ANNOT_UNKNOWN
+	AnnotationSubtypeText           AnnotationSubtype = 1  // FPDF_ANNOT_TEXT
+	AnnotationSubtypeLink           AnnotationSubtype = 2  // FPDF_ANNOT_LINK
+	AnnotationSubtypeFreeText       AnnotationSubtype = 3  // FPDF_ANNOT_FREETEXT
+	AnnotationSubtypeLine           AnnotationSubtype = 4  // FPDF_ANNOT_LINE
+	AnnotationSubtypeSquare         AnnotationSubtype = 5  // FPDF_ANNOT_SQUARE
+	AnnotationSubtypeCircle         AnnotationSubtype = 6  // FPDF_ANNOT_CIRCLE
+	AnnotationSubtypePolygon        AnnotationSubtype = 7  // FPDF_ANNOT_POLYGON
+	AnnotationSubtypePolyLine       AnnotationSubtype = 8  // FPDF_ANNOT_POLYLINE
+	AnnotationSubtypeHighlight      AnnotationSubtype = 9  // FPDF_ANNOT_HIGHLIGHT
+	AnnotationSubtypeUnderline      AnnotationSubtype = 10 // FPDF_ANNOT_UNDERLINE
+	AnnotationSubtypeSquiggly       AnnotationSubtype = 11 // FPDF_ANNOT_SQUIGGLY
+	AnnotationSubtypeStrikeOut      AnnotationSubtype = 12 // FPDF_ANNOT_STRIKEOUT
+	AnnotationSubtypeStamp          AnnotationSubtype = 13 // FPDF_ANNOT_STAMP
+	AnnotationSubtypeCaret          AnnotationSubtype = 14 // FPDF_ANNOT_CARET
+	AnnotationSubtypeInk            AnnotationSubtype = 15 // FPDF_ANNOT_INK
+	AnnotationSubtypePopup          AnnotationSubtype = 16 // FPDF_ANNOT_POPUP
+	AnnotationSubtypeFileAttachment AnnotationSubtype = 17 // FPDF_ANNOT_FILEATTACHMENT
+	AnnotationSubtypeSound          AnnotationSubtype = 18 // FPDF_ANNOT_SOUND
+	AnnotationSubtypeMovie          AnnotationSubtype = 19 // FPDF_ANNOT_MOVIE
+	AnnotationSubtypeWidget         AnnotationSubtype = 20 // FPDF_ANNOT_WIDGET
+	AnnotationSubtypeScreen         AnnotationSubtype = 21 // FPDF_ANNOT_SCREEN
+	AnnotationSubtypePrinterMark    AnnotationSubtype = 22 // FPDF_ANNOT_PRINTERMARK
+	AnnotationSubtypeTrapNet        AnnotationSubtype = 23 // FPDF_ANNOT_TRAPNET
+	AnnotationSubtypeWatermark      AnnotationSubtype = 24 // FPDF_ANNOT_WATERMARK
+	AnnotationSubtypeThreeD         AnnotationSubtype = 25 // FPDF_ANNOT_THREED
+	AnnotationSubtypeRichMedia      AnnotationSubtype = 26 // FPDF_ANNOT_RICHMEDIA
+	AnnotationSubtypeXFAWidget      AnnotationSubtype = 27 // FPDF_ANNOT_XFAWIDGET
+	AnnotationSubtypeRedact         AnnotationSubtype = 28 // FPDF_ANNOT_REDACT
+)
+
+// AnnotationFlags are the flags of an annotation, see section 12.5.3 of the
+// PDF 1.7 specification.
+type AnnotationFlags int
+
+const (
+	AnnotationFlagInvisible    AnnotationFlags = 1 << 0 // FPDF_ANNOT_FLAG_INVISIBLE
+	AnnotationFlagHidden       AnnotationFlags = 1 << 1 // FPDF_ANNOT_FLAG_HIDDEN
+	AnnotationFlagPrint        AnnotationFlags = 1 << 2 // FPDF_ANNOT_FLAG_PRINT
+	AnnotationFlagNoZoom       AnnotationFlags = 1 << 3 // FPDF_ANNOT_FLAG_NOZOOM
+	AnnotationFlagNoRotate     AnnotationFlags = 1 << 4 // FPDF_ANNOT_FLAG_NOROTATE
+	AnnotationFlagNoView       AnnotationFlags = 1 << 5 // FPDF_ANNOT_FLAG_NOVIEW
+	AnnotationFlagReadOnly     AnnotationFlags = 1 << 6 // FPDF_ANNOT_FLAG_READONLY
+	AnnotationFlagLocked       AnnotationFlags = 1 << 7 // FPDF_ANNOT_FLAG_LOCKED
+	AnnotationFlagToggleNoView AnnotationFlags = 1 << 8 // FPDF_ANNOT_FLAG_TOGGLENOVIEW
+)
+
+// annotationColorType is FPDFANNOT_COLORTYPE.
+type annotationColorType int
+
+const (
+	annotationColorTypeColor         annotationColorType = 0 // FPDFANNOT_COLORTYPE_Color
+	annotationColorTypeInteriorColor annotationColorType = 1 // FPDFANNOT_COLORTYPE_InteriorColor
+)
+
+// Annotation is an annotation on a page. It is one of the *...Annotation
+// types of this package, depending on its subtype.
+type Annotation interface {
+	// Common returns the properties all annotations have.
+	Common() *AnnotationCommon
+}
+
+// AnnotationCommon holds the properties all annotations have.
+type AnnotationCommon struct {
+	// Index is the index of the annotation on its page. It changes when an
+	// annotation before it is removed.
+	Index int
+
+	Subtype AnnotationSubtype
+
+	// Rect is the area of the annotation in page coordinates.
+	Rect Rect
+
+	// Color is nil when it is not set, or when the annotation has an
+	// appearance stream, whose colors take priority.
+	Color color.Color
+
+	// Contents is the text of the annotation, like the comment of a note.
+	Contents string
+
+	// Author is the T entry, the name of the user who created the
+	// annotation.
+	Author string
+
+	// CreationDate and ModDate are zero when they are not set or can't be
+	// parsed.
+	CreationDate time.Time
+	ModDate      time.Time
+
+	Flags AnnotationFlags
+}
+
+// Common implements Annotation.
+func (c *AnnotationCommon) Common() *AnnotationCommon {
+	return c
+}
+
+// TextAnnotation is a sticky note.
+type TextAnnotation struct {
+	AnnotationCommon
+
+	// Icon is the name of the icon of the note, like Comment or Note.
+	Icon string
+}
+
+// LinkAnnotation is a link.
+type LinkAnnotation struct {
+	AnnotationCommon
+
+	Link Link
+}
+
+// FreeTextAnnotation shows its Contents directly on the page.
+type FreeTextAnnotation struct {
+	AnnotationCommon
+
+	// DefaultAppearance is the DA entry, the operators that set the font and
+	// color of the text.
+	DefaultAppearance string
+}
+
+// LineAnnotation is a straight line.
+type LineAnnotation struct {
+	AnnotationCommon
+
+	Start Point
+	End   Point
+}
+
+// ShapeAnnotation is a square or circle annotation, a rectangle or an
+// ellipse inside Rect.
+type ShapeAnnotation struct {
+	AnnotationCommon
+
+	// InteriorColor fills the shape, it is nil like Color.
+	InteriorColor color.Color
+}
+
+// PolygonAnnotation is a polygon or polyline annotation.
+type PolygonAnnotation struct {
+	AnnotationCommon
+
+	Vertices []Point
+
+	// InteriorColor fills a polygon, it is nil like Color.
+	InteriorColor color.Color
+}
+
+// TextMarkupAnnotation is a highlight, underline, squiggly or strikeout
+// annotation.
+type TextMarkupAnnotation struct {
+	AnnotationCommon
+
+	// QuadPoints cover the marked up text, usually one per line.
+	QuadPoints []Quad
+}
+
+// StampAnnotation is a rubber stamp.
+type StampAnnotation struct {
+	AnnotationCommon
+
+	// Icon is the name of the stamp, like Approved or Draft.
+	Icon string
+}
+
+// InkAnnotation is a freehand drawing.
+type InkAnnotation struct {
+	AnnotationCommon
+
+	// InkList are the strokes of the drawing.
+	InkList [][]Point
+}
+
+// GenericAnnotation is an annotation of a subtype without its own type.
+type GenericAnnotation struct {
+	AnnotationCommon
+}
+
+// AnnotationCount returns the number of annotations on the page.
+func (p *Page) AnnotationCount(ctx context.Context) (int, error) {
+	count, err := p.document.instance.call1(ctx, "FPDFPage_GetAnnotCount", p.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(int32(count)), nil
+}
+
+// Annotations returns the annotations on the page.
+func (p *Page) Annotations(ctx context.Context) ([]Annotation, error) {
+	count, err := p.AnnotationCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := make([]Annotation, 0, count)
+	for index := 0; index < count; index++ {
+		annotation, err := p.GetAnnotation(ctx, index)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations = append(annotations, annotation)
+	}
+
+	return annotations, nil
+}
+
+// GetAnnotation returns the annotation at index on the page.
+func (p *Page) GetAnnotation(ctx context.Context, index int) (Annotation, error) {
+	handle, err := p.annotationHandle(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+	defer p.document.instance.call(ctx, "FPDFPage_CloseAnnot", handle)
+
+	return p.annotation(ctx, handle, index)
+}
+
+// annotationHandle returns the FPDF_ANNOTATION handle at index, which must
+// be closed with FPDFPage_CloseAnnot.
+func (p *Page) annotationHandle(ctx context.Context, index int) (uint64, error) {
+	handle, err := p.document.instance.call1(ctx, "FPDFPage_GetAnnot", p.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return 0, err
+	}
+
+	if handle == 0 {
+		return 0, fmt.Errorf("pdfium: annotation %d not found", index)
+	}
+
+	return handle, nil
+}
+
+// annotation reads the FPDF_ANNOTATION handle at index.
+func (p *Page) annotation(ctx context.Context, handle uint64, index int) (Annotation, error) {
+	common, err := p.annotationCommon(ctx, handle, index)
+	if err != nil {
+		return nil, err
+	}
+
+	switch common.Subtype {
+	case AnnotationSubtypeText:
+		annotation := &TextAnnotation{AnnotationCommon: *common}
+		if annotation.Icon, err = p.annotationStringValue(ctx, handle, "Name"); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeLink:
+		annotation := &LinkAnnotation{AnnotationCommon: *common}
+		linkHandle, err := p.document.instance.call1(ctx, "FPDFAnnot_GetLink", handle)
+		if err != nil {
+			return nil, err
+		}
+		if linkHandle != 0 {
+			link, err := p.link(ctx, linkHandle, nil)
+			if err != nil {
+				return nil, err
+			}
+			annotation.Link = *link
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeFreeText:
+		annotation := &FreeTextAnnotation{AnnotationCommon: *common}
+		if annotation.DefaultAppearance, err = p.annotationStringValue(ctx, handle, "DA"); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeLine:
+		annotation := &LineAnnotation{AnnotationCommon: *common}
+		i := p.document.instance
+		values, err := i.callOutFloat32s(ctx, "FPDFAnnot_GetLine", 4, func(pointer uint64) []uint64 {
+			return []uint64{handle, pointer, pointer + 8}
+		})
+		if err != nil && !isFalse(err) {
+			return nil, err
+		}
+
+		if err == nil {
+			annotation.Start = Point{X: float64(values[0]), Y: float64(values[1])}
+			annotation.End = Point{X: float64(values[2]), Y: float64(values[3])}
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeSquare, AnnotationSubtypeCircle:
+		annotation := &ShapeAnnotation{AnnotationCommon: *common}
+		if annotation.InteriorColor, err = p.annotationColor(ctx, handle, annotationColorTypeInteriorColor); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	case AnnotationSubtypePolygon, AnnotationSubtypePolyLine:
+		annotation := &PolygonAnnotation{AnnotationCommon: *common}
+		if annotation.Vertices, err = p.annotationPoints(ctx, "FPDFAnnot_GetVertices", handle); err != nil {
+			return nil, err
+		}
+		if annotation.InteriorColor, err = p.annotationColor(ctx, handle, annotationColorTypeInteriorColor); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeHighlight, AnnotationSubtypeUnderline, AnnotationSubtypeSquiggly, AnnotationSubtypeStrikeOut:
+		annotation := &TextMarkupAnnotation{AnnotationCommon: *common}
+		if annotation.QuadPoints, err = p.annotationQuadPoints(ctx, handle); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeStamp:
+		annotation := &StampAnnotation{AnnotationCommon: *common}
+		if annotation.Icon, err = p.annotationStringValue(ctx, handle, "Name"); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	case AnnotationSubtypeInk:
+		annotation := &InkAnnotation{AnnotationCommon: *common}
+		if annotation.InkList, err = p.annotationInkList(ctx, handle); err != nil {
+			return nil, err
+		}
+		return annotation, nil
+
+	default:
+		return &GenericAnnotation{AnnotationCommon: *common}, nil
+	}
+}
+
+// annotationCommon reads the properties all annotations have.
+func (p *Page) annotationCommon(ctx context.Context, handle uint64, index int) (*AnnotationCommon, error) {
+	i := p.document.instance
+
+	common := &AnnotationCommon{
+		Index: index,
+	}
+
+	subtype, err := i.call1(ctx, "FPDFAnnot_GetSubtype", handle)
+	if err != nil {
+		return nil, err
+	}
+	common.Subtype = AnnotationSubtype(int32(subtype))
+
+	// Annotations without a valid rectangle keep an empty one.
+	values, err := i.callOutFloat32s(ctx, "FPDFAnnot_GetRect", 4, func(pointer uint64) []uint64 {
+		return []uint64{handle, pointer}
+	})
+	if err != nil && !isFalse(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		common.Rect = Rect{Left: float64(values[0]), Top: float64(values[1]), Right: float64(values[2]), Bottom: float64(values[3])}
+	}
+
+	if common.Color, err = p.annotationColor(ctx, handle, annotationColorTypeColor); err != nil {
+		return nil, err
+	}
+
+	if common.Contents, err = p.annotationStringValue(ctx, handle, "Contents"); err != nil {
+		return nil, err
+	}
+
+	if common.Author, err = p.annotationStringValue(ctx, handle, "T"); err != nil {
+		return nil, err
+	}
+
+	dates := []struct {
+		key   string
+		value *time.Time
+	}{
+		{"CreationDate", &common.CreationDate},
+		{"M", &common.ModDate},
+	}
+
+	for _, date := range dates {
+		value, err := p.annotationStringValue(ctx, handle, date.key)
+		if err != nil {
+			return nil, err
+		}
+
+		if parsed, err := ParseDate(value); err == nil {
+			*date.value = parsed
+		}
+	}
+
+	flags, err := i.call1(ctx, "FPDFAnnot_GetFlags", handle)
+	if err != nil {
+		return nil, err
+	}
+	common.Flags = AnnotationFlags(int32(flags))
+
+	return common, nil
+}
+
+// annotationStringValue returns the value of key in the dictionary of the
+// annotation handle.
+func (p *Page) annotationStringValue(ctx context.Context, handle uint64, key string) (string, error) {
+	i := p.document.instance
+
+	keyPointer, err := i.allocCString(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, keyPointer)
+
+	return i.callUTF16(ctx, "FPDFAnnot_GetStringValue", handle, keyPointer)
+}
+
+// annotationColor returns the color of colorType of the annotation handle,
+// or nil when it has none.
+func (p *Page) annotationColor(ctx context.Context, handle uint64, colorType annotationColorType) (color.Color, error) {
+	i := p.document.instance
+
+	// Four unsigned ints for R, G, B and A.
+	pointer, err := i.alloc(ctx, 16)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, "FPDFAnnot_GetColor", handle, uint64(colorType), pointer, pointer+4, pointer+8, pointer+12)
+	if err != nil {
+		return nil, err
+	}
+
+	if success == 0 {
+		return nil, nil
+	}
+
+	values, err := i.readInt32s(ctx, pointer, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	return color.NRGBA{R: uint8(values[0]), G: uint8(values[1]), B: uint8(values[2]), A: uint8(values[3])}, nil
+}
+
+// annotationQuadPoints returns the attachment points of the annotation
+// handle.
+func (p *Page) annotationQuadPoints(ctx context.Context, handle uint64) ([]Quad, error) {
+	i := p.document.instance
+
+	count, err := i.call1(ctx, "FPDFAnnot_CountAttachmentPoints", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	quads := make([]Quad, 0, uint32(count))
+	for quadIndex := uint32(0); quadIndex < uint32(count); quadIndex++ {
+		// An FS_QUADPOINTSF, x1, y1 up to x4, y4.
+		values, err := i.callOutFloat32s(ctx, "FPDFAnnot_GetAttachmentPoints", 8, func(pointer uint64) []uint64 {
+			return []uint64{handle, uint64(quadIndex), pointer}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		quad := Quad{}
+		for n := range quad {
+			quad[n] = Point{X: float64(values[n*2]), Y: float64(values[n*2+1])}
+		}
+		quads = append(quads, quad)
+	}
+
+	return quads, nil
+}
+
+// annotationInkList returns the strokes of the ink annotation handle.
+func (p *Page) annotationInkList(ctx context.Context, handle uint64) ([][]Point, error) {
+	i := p.document.instance
+
+	count, err := i.call1(ctx, "FPDFAnnot_GetInkListCount", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	inkList := make([][]Point, 0, uint32(count))
+	for pathIndex := uint32(0); pathIndex < uint32(count); pathIndex++ {
+		path, err := p.annotationPoints(ctx, "FPDFAnnot_GetInkListPath", handle, uint64(pathIndex))
+		if err != nil {
+			return nil, err
+		}
+
+		inkList = append(inkList, path)
+	}
+
+	return inkList, nil
+}
+
+// annotationPoints calls the PDFium function with the given name, that
+// writes FS_POINTFs to a buffer given as its last two parameters and returns
+// their number. It is first called without a buffer to get the number.
+func (p *Page) annotationPoints(ctx context.Context, name string, params ...uint64) ([]Point, error) {
+	i := p.document.instance
+
+	count, err := i.call1(ctx, name, append(params[:len(params):len(params)], 0, 0)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint32(count) == 0 {
+		return nil, nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, uint64(uint32(count))*8)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, name, append(params[:len(params):len(params)], bufferPointer, uint64(uint32(count)))...); err != nil {
+		return nil, err
+	}
+
+	values, err := i.readFloat32s(ctx, bufferPointer, int(uint32(count))*2)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, int(uint32(count)))
+	for n := range points {
+		points[n] = Point{X: float64(values[n*2]), Y: float64(values[n*2+1])}
+	}
+
+	return points, nil
+}
@@ -0,0 +1,406 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// AppearanceMode selects one of the appearance streams of an annotation.
+type AppearanceMode int
+
+const (
+	AppearanceModeNormal   AppearanceMode = 0 // FPDF_ANNOT_APPEARANCEMODE_NORMAL
+	AppearanceModeRollover AppearanceMode = 1 // FPDF_ANNOT_APPEARANCEMODE_ROLLOVER
+	AppearanceModeDown     AppearanceMode = 2 // FPDF_ANNOT_APPEARANCEMODE_DOWN
+)
+
+// AddAnnotation creates an annotation on the page from annotation and
+// returns its index. Subtype may be left zero for the types that have one
+// subtype. Index and the fields PDFium can't write, like Icon and the
+// vertices of a polygon, are ignored. PDFium generates the appearance of
+// most subtypes when the page is rendered, stamps need one set by
+// SetAnnotationAppearance.
+func (p *Page) AddAnnotation(ctx context.Context, annotation Annotation) (int, error) {
+	i := p.document.instance
+
+	subtype, err := annotationSubtype(annotation)
+	if err != nil {
+		return 0, err
+	}
+
+	// FPDFPage_CreateAnnot appends the annotation, so its index is known up
+	// front and every failure after creating it can be rolled back.
+	index, err := p.AnnotationCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	handle, err := i.call1(ctx, "FPDFPage_CreateAnnot", p.handle, api.EncodeI32(int32(subtype)))
+	if err != nil {
+		return 0, err
+	}
+
+	if handle == 0 {
+		return 0, fmt.Errorf("pdfium: could not create annotation of subtype %d", subtype)
+	}
+	defer i.call(ctx, "FPDFPage_CloseAnnot", handle)
+
+	// Prefer the index PDFium reports, in case the annotation did not end up
+	// last after all.
+	actualIndex, err := i.call1(ctx, "FPDFPage_GetAnnotIndex", p.handle, handle)
+	if err == nil && int32(actualIndex) >= 0 {
+		index = int(int32(actualIndex))
+	}
+
+	if err == nil {
+		err = p.setAnnotation(ctx, handle, annotation, true)
+	}
+
+	if err != nil {
+		if removeErr := p.RemoveAnnotation(ctx, index); removeErr != nil {
+			return 0, fmt.Errorf("%w (removing the annotation failed too: %v)", err, removeErr)
+		}
+		return 0, err
+	}
+
+	return index, nil
+}
+
+// UpdateAnnotation writes annotation to the annotation at its index, which
+// must have the same subtype. Quad points can only be added or changed, not
+// removed. PDFium can't change the colors of annotations that have an
+// appearance stream, so remove it first with SetAnnotationAppearance.
+func (p *Page) UpdateAnnotation(ctx context.Context, annotation Annotation) error {
+	i := p.document.instance
+	common := annotation.Common()
+
+	subtype, err := annotationSubtype(annotation)
+	if err != nil {
+		return err
+	}
+
+	handle, err := p.annotationHandle(ctx, common.Index)
+	if err != nil {
+		return err
+	}
+	defer i.call(ctx, "FPDFPage_CloseAnnot", handle)
+
+	current, err := i.call1(ctx, "FPDFAnnot_GetSubtype", handle)
+	if err != nil {
+		return err
+	}
+
+	if AnnotationSubtype(int32(current)) != subtype {
+		return fmt.Errorf("pdfium: annotation %d has subtype %d, not %d", common.Index, int32(current), subtype)
+	}
+
+	return p.setAnnotation(ctx, handle, annotation, false)
+}
+
+// RemoveAnnotation removes the annotation at index from the page.
+func (p *Page) RemoveAnnotation(ctx context.Context, index int) error {
+	success, err := p.document.instance.call1(ctx, "FPDFPage_RemoveAnnot", p.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not remove annotation %d", index)
+	}
+
+	return nil
+}
+
+// SetAnnotationAppearance sets the appearance stream of mode of the
+// annotation at index to the content stream operators in stream. An empty
+// stream removes the appearance stream, for the normal mode all of them.
+func (p *Page) SetAnnotationAppearance(ctx context.Context, index int, mode AppearanceMode, stream string) error {
+	i := p.document.instance
+
+	handle, err := p.annotationHandle(ctx, index)
+	if err != nil {
+		return err
+	}
+	defer i.call(ctx, "FPDFPage_CloseAnnot", handle)
+
+	// A NULL value removes the appearance stream.
+	var streamPointer uint64
+	if stream != "" {
+		if streamPointer, err = i.allocWideString(ctx, stream); err != nil {
+			return err
+		}
+		defer i.release(ctx, streamPointer)
+	}
+
+	success, err := i.call1(ctx, "FPDFAnnot_SetAP", handle, api.EncodeI32(int32(mode)), streamPointer)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not set appearance of annotation %d", index)
+	}
+
+	return nil
+}
+
+// annotationSubtype returns the subtype of annotation, defaulting to the
+// subtype of its type.
+func annotationSubtype(annotation Annotation) (AnnotationSubtype, error) {
+	if subtype := annotation.Common().Subtype; subtype != AnnotationSubtypeUnknown {
+		return subtype, nil
+	}
+
+	switch annotation.(type) {
+	case *TextAnnotation:
+		return AnnotationSubtypeText, nil
+	case *LinkAnnotation:
+		return AnnotationSubtypeLink, nil
+	case *FreeTextAnnotation:
+		return AnnotationSubtypeFreeText, nil
+	case *LineAnnotation:
+		return AnnotationSubtypeLine, nil
+	case *StampAnnotation:
+		return AnnotationSubtypeStamp, nil
+	case *InkAnnotation:
+		return AnnotationSubtypeInk, nil
+	}
+
+	return 0, errors.New("pdfium: annotation subtype is not set")
+}
+
+// setAnnotation writes annotation to the FPDF_ANNOTATION handle. When create
+// is true, empty strings and zero values are not written.
+func (p *Page) setAnnotation(ctx context.Context, handle uint64, annotation Annotation, create bool) error {
+	i := p.document.instance
+	common := annotation.Common()
+
+	if !create || common.Rect != (Rect{}) {
+		if err := p.setAnnotationRect(ctx, handle, common.Rect); err != nil {
+			return err
+		}
+	}
+
+	if common.Color != nil {
+		if err := p.setAnnotationColor(ctx, handle, annotationColorTypeColor, common.Color); err != nil {
+			return err
+		}
+	}
+
+	type stringValue struct {
+		key   string
+		value string
+	}
+
+	values := []stringValue{
+		{"Contents", common.Contents},
+		{"T", common.Author},
+	}
+
+	if !common.CreationDate.IsZero() {
+		values = append(values, stringValue{"CreationDate", FormatDate(common.CreationDate)})
+	}
+
+	if !common.ModDate.IsZero() {
+		values = append(values, stringValue{"M", FormatDate(common.ModDate)})
+	}
+
+	switch annotation := annotation.(type) {
+	case *FreeTextAnnotation:
+		values = append(values, stringValue{"DA", annotation.DefaultAppearance})
+
+	case *ShapeAnnotation:
+		if annotation.InteriorColor != nil {
+			if err := p.setAnnotationColor(ctx, handle, annotationColorTypeInteriorColor, annotation.InteriorColor); err != nil {
+				return err
+			}
+		}
+
+	case *PolygonAnnotation:
+		if annotation.InteriorColor != nil {
+			if err := p.setAnnotationColor(ctx, handle, annotationColorTypeInteriorColor, annotation.InteriorColor); err != nil {
+				return err
+			}
+		}
+
+	case *TextMarkupAnnotation:
+		if err := p.setAnnotationQuadPoints(ctx, handle, annotation.QuadPoints); err != nil {
+			return err
+		}
+
+	case *InkAnnotation:
+		if err := p.setAnnotationInkList(ctx, handle, annotation.InkList, create); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range values {
+		if create && value.value == "" {
+			continue
+		}
+
+		if err := p.setAnnotationStringValue(ctx, handle, value.key, value.value); err != nil {
+			return err
+		}
+	}
+
+	success, err := i.call1(ctx, "FPDFAnnot_SetFlags", handle, api.EncodeI32(int32(common.Flags)))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not set annotation flags")
+	}
+
+	return nil
+}
+
+// setAnnotationRect sets the rectangle of the annotation handle.
+func (p *Page) setAnnotationRect(ctx context.Context, handle uint64, rect Rect) error {
+	i := p.document.instance
+
+	rectPointer, err := i.allocFloat32s(ctx, []float32{float32(rect.Left), float32(rect.Top), float32(rect.Right), float32(rect.Bottom)})
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, rectPointer)
+
+	success, err := i.call1(ctx, "FPDFAnnot_SetRect", handle, rectPointer)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not set annotation rect")
+	}
+
+	return nil
+}
+
+// setAnnotationColor sets the color of colorType of the annotation handle.
+func (p *Page) setAnnotationColor(ctx context.Context, handle uint64, colorType annotationColorType, c color.Color) error {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	success, err := p.document.instance.call1(ctx, "FPDFAnnot_SetColor", handle, uint64(colorType), uint64(n.R), uint64(n.G), uint64(n.B), uint64(n.A))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not set annotation color, it may have an appearance stream")
+	}
+
+	return nil
+}
+
+// setAnnotationStringValue sets key in the dictionary of the annotation
+// handle.
+func (p *Page) setAnnotationStringValue(ctx context.Context, handle uint64, key, value string) error {
+	i := p.document.instance
+
+	keyPointer, err := i.allocCString(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, keyPointer)
+
+	valuePointer, err := i.allocWideString(ctx, value)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, valuePointer)
+
+	success, err := i.call1(ctx, "FPDFAnnot_SetStringValue", handle, keyPointer, valuePointer)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not set %s of annotation", key)
+	}
+
+	return nil
+}
+
+// setAnnotationQuadPoints replaces the existing attachment points of the
+// annotation handle and appends the rest.
+func (p *Page) setAnnotationQuadPoints(ctx context.Context, handle uint64, quads []Quad) error {
+	i := p.document.instance
+
+	count, err := i.call1(ctx, "FPDFAnnot_CountAttachmentPoints", handle)
+	if err != nil {
+		return err
+	}
+
+	for quadIndex, quad := range quads {
+		values := make([]float32, 0, 8)
+		for _, point := range quad {
+			values = append(values, float32(point.X), float32(point.Y))
+		}
+
+		quadPointer, err := i.allocFloat32s(ctx, values)
+		if err != nil {
+			return err
+		}
+
+		var success uint64
+		if uint32(quadIndex) < uint32(count) {
+			success, err = i.call1(ctx, "FPDFAnnot_SetAttachmentPoints", handle, uint64(quadIndex), quadPointer)
+		} else {
+			success, err = i.call1(ctx, "FPDFAnnot_AppendAttachmentPoints", handle, quadPointer)
+		}
+		i.release(ctx, quadPointer)
+
+		if err != nil {
+			return err
+		}
+
+		if success == 0 {
+			return fmt.Errorf("pdfium: could not set annotation quad points %d", quadIndex)
+		}
+	}
+
+	return nil
+}
+
+// setAnnotationInkList adds the strokes to the ink annotation handle,
+// replacing its strokes unless it was just created.
+func (p *Page) setAnnotationInkList(ctx context.Context, handle uint64, inkList [][]Point, create bool) error {
+	i := p.document.instance
+
+	if !create {
+		if _, err := i.call(ctx, "FPDFAnnot_RemoveInkList", handle); err != nil {
+			return err
+		}
+	}
+
+	for strokeIndex, stroke := range inkList {
+		values := make([]float32, 0, len(stroke)*2)
+		for _, point := range stroke {
+			values = append(values, float32(point.X), float32(point.Y))
+		}
+
+		pointsPointer, err := i.allocFloat32s(ctx, values)
+		if err != nil {
+			return err
+		}
+
+		result, err := i.call1(ctx, "FPDFAnnot_AddInkStroke", handle, pointsPointer, uint64(len(stroke)))
+		i.release(ctx, pointsPointer)
+
+		if err != nil {
+			return err
+		}
+
+		if int32(result) < 0 {
+			return fmt.Errorf("pdfium: could not add ink stroke %d", strokeIndex)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package pdfium
+
+import "testing"
+
+func TestAnnotationSubtype(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation Annotation
+		want       AnnotationSubtype
+	}{
+		{name: "text", annotation: &TextAnnotation{}, want: AnnotationSubtypeText},
+		{name: "link", annotation: &LinkAnnotation{}, want: AnnotationSubtypeLink},
+		{name: "free text", annotation: &FreeTextAnnotation{}, want: AnnotationSubtypeFreeText},
+		{name: "line", annotation: &LineAnnotation{}, want: AnnotationSubtypeLine},
+		{name: "stamp", annotation: &StampAnnotation{}, want: AnnotationSubtypeStamp},
+		{name: "ink", annotation: &InkAnnotation{}, want: AnnotationSubtypeInk},
+		{
+			name:       "set subtype",
+			annotation: &AnnotationCommon{Subtype: AnnotationSubtypeSquare},
+			want:       AnnotationSubtypeSquare,
+		},
+		{
+			name:       "set subtype wins",
+			annotation: &TextAnnotation{AnnotationCommon: AnnotationCommon{Subtype: AnnotationSubtypeCaret}},
+			want:       AnnotationSubtypeCaret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := annotationSubtype(test.annotation)
+			if err != nil {
+				t.Fatalf("annotationSubtype() error: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("annotationSubtype() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAnnotationSubtypeNotSet(t *testing.T) {
+	if _, err := annotationSubtype(&AnnotationCommon{}); err == nil {
+		t.Error("annotationSubtype() error = nil, want an error")
+	}
+}
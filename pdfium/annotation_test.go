@@ -0,0 +1,93 @@
+package pdfium
+
+import (
+	"context"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// newAnnotationPage returns a page whose annotations have a color but no
+// interior color, one quad and an ink list with one stroke of two points.
+func newAnnotationPage(t *testing.T) *Page {
+	t.Helper()
+
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFAnnot_GetColor": {
+			params:  i32s(6),
+			results: i32s(1),
+			body: join(
+				localGet(1), []byte{opIf, api.ValueTypeI32},
+				i32Const(0),
+				[]byte{opElse},
+				storeInt32s(2, 0x12), storeInt32s(3, 0x34), storeInt32s(4, 0x56), storeInt32s(5, 0x78),
+				i32Const(1),
+				[]byte{opEnd},
+			),
+		},
+		"FPDFAnnot_CountAttachmentPoints": returnI32(i32s(1), 1),
+		"FPDFAnnot_GetAttachmentPoints": {
+			params:  i32s(3),
+			results: i32s(1),
+			body:    join(storeFloat32s(2, 1, 2, 3, 4, 5, 6, 7, 8), i32Const(1)),
+		},
+		"FPDFAnnot_GetInkListCount": returnI32(i32s(1), 1),
+		"FPDFAnnot_GetInkListPath": {
+			params:  i32s(4),
+			results: i32s(1),
+			body: join(
+				localGet(2), []byte{opIf, 0x40},
+				storeFloat32s(2, 1, 2, 3, 4),
+				[]byte{opEnd},
+				i32Const(2),
+			),
+		},
+	})
+
+	return &Page{document: &Document{instance: i, handle: 1}, handle: 1}
+}
+
+func TestAnnotationColor(t *testing.T) {
+	p := newAnnotationPage(t)
+	ctx := context.Background()
+
+	c, err := p.annotationColor(ctx, 1, annotationColorTypeColor)
+	if err != nil {
+		t.Fatalf("annotationColor() error: %v", err)
+	}
+
+	if want := (color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0x78}); c != want {
+		t.Errorf("annotationColor() = %v, want %v", c, want)
+	}
+
+	c, err = p.annotationColor(ctx, 1, annotationColorTypeInteriorColor)
+	if err != nil || c != nil {
+		t.Errorf("annotationColor() of a missing color = %v, %v, want nil, nil", c, err)
+	}
+}
+
+func TestAnnotationQuadPoints(t *testing.T) {
+	quads, err := newAnnotationPage(t).annotationQuadPoints(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("annotationQuadPoints() error: %v", err)
+	}
+
+	want := []Quad{{{X: 1, Y: 2}, {X: 3, Y: 4}, {X: 5, Y: 6}, {X: 7, Y: 8}}}
+	if !reflect.DeepEqual(quads, want) {
+		t.Errorf("annotationQuadPoints() = %v, want %v", quads, want)
+	}
+}
+
+func TestAnnotationInkList(t *testing.T) {
+	inkList, err := newAnnotationPage(t).annotationInkList(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("annotationInkList() error: %v", err)
+	}
+
+	want := [][]Point{{{X: 1, Y: 2}, {X: 3, Y: 4}}}
+	if !reflect.DeepEqual(inkList, want) {
+		t.Errorf("annotationInkList() = %v, want %v", inkList, want)
+	}
+}
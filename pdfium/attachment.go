@@ -0,0 +1,384 @@
+package pdfium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Attachment is a file embedded in a document.
+type Attachment struct {
+	document *Document
+
+	// Index is the index of the attachment in the document. It changes when
+	// an attachment before it is deleted.
+	Index int
+
+	Name string
+
+	// Subtype is the MIME type of the file, empty when it is unknown or the
+	// PDFium build can't read it.
+	Subtype string
+
+	// CreationDate and ModDate are zero when they are not set or can't be
+	// parsed.
+	CreationDate time.Time
+	ModDate      time.Time
+
+	// CheckSum is the MD5 checksum of the file as a hex string, empty when it
+	// is not set.
+	CheckSum string
+}
+
+// AttachmentOptions configures Document.AddAttachment.
+type AttachmentOptions struct {
+	// CreationDate and ModDate are stored when they are not zero.
+	CreationDate time.Time
+	ModDate      time.Time
+}
+
+// AttachmentCount returns the number of embedded files in the document.
+func (d *Document) AttachmentCount(ctx context.Context) (int, error) {
+	count, err := d.instance.call1(ctx, "FPDFDoc_GetAttachmentCount", d.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(int32(count)), nil
+}
+
+// Attachments returns the embedded files of the document. Their contents are
+// only read by Attachment.Open.
+func (d *Document) Attachments(ctx context.Context) ([]Attachment, error) {
+	count, err := d.AttachmentCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make([]Attachment, 0, count)
+	for index := 0; index < count; index++ {
+		attachment, err := d.GetAttachment(ctx, index)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, *attachment)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachment returns the embedded file at index.
+func (d *Document) GetAttachment(ctx context.Context, index int) (*Attachment, error) {
+	handle, err := d.attachmentHandle(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.attachment(ctx, handle, index)
+}
+
+// attachment reads the FPDF_ATTACHMENT handle at index.
+func (d *Document) attachment(ctx context.Context, handle uint64, index int) (*Attachment, error) {
+	i := d.instance
+
+	attachment := &Attachment{
+		document: d,
+		Index:    index,
+	}
+
+	var err error
+	if attachment.Name, err = i.callUTF16(ctx, "FPDFAttachment_GetName", handle); err != nil {
+		return nil, err
+	}
+
+	// FPDFAttachment_GetSubtype is not available in every PDFium build.
+	if i.mod.ExportedFunction("FPDFAttachment_GetSubtype") != nil {
+		if attachment.Subtype, err = i.callUTF16(ctx, "FPDFAttachment_GetSubtype", handle); err != nil {
+			return nil, err
+		}
+	}
+
+	if attachment.CheckSum, err = d.attachmentStringValue(ctx, handle, "CheckSum"); err != nil {
+		return nil, err
+	}
+
+	dates := []struct {
+		key   string
+		value *time.Time
+	}{
+		{"CreationDate", &attachment.CreationDate},
+		{"ModDate", &attachment.ModDate},
+	}
+
+	for _, date := range dates {
+		value, err := d.attachmentStringValue(ctx, handle, date.key)
+		if err != nil {
+			return nil, err
+		}
+
+		if parsed, err := ParseDate(value); err == nil {
+			*date.value = parsed
+		}
+	}
+
+	return attachment, nil
+}
+
+// Open reads the contents of the attachment.
+func (a *Attachment) Open(ctx context.Context) (io.Reader, error) {
+	d := a.document
+	i := d.instance
+
+	handle, err := d.attachmentHandle(ctx, a.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	lengthPointer, err := i.alloc(ctx, 4)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, lengthPointer)
+
+	success, err := i.call1(ctx, "FPDFAttachment_GetFile", handle, 0, 0, lengthPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if success == 0 {
+		return nil, fmt.Errorf("pdfium: attachment %s has no file", a.Name)
+	}
+
+	length, ok := i.mod.Memory().ReadUint32Le(ctx, uint32(lengthPointer))
+	if !ok {
+		return nil, errors.New("pdfium: could not read attachment length")
+	}
+
+	if length == 0 {
+		return bytes.NewReader(nil), nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, uint64(length))
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, "FPDFAttachment_GetFile", handle, bufferPointer, uint64(length), lengthPointer); err != nil {
+		return nil, err
+	}
+
+	data, err := i.read(ctx, bufferPointer, uint64(length))
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// AddAttachment embeds a file with the given name and contents in the
+// document.
+func (d *Document) AddAttachment(ctx context.Context, name string, data []byte, options AttachmentOptions) (*Attachment, error) {
+	i := d.instance
+
+	namePointer, err := i.allocWideString(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, namePointer)
+
+	handle, err := i.call1(ctx, "FPDFDoc_AddAttachment", d.handle, namePointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, fmt.Errorf("pdfium: could not add attachment %s, the name may already be in use", name)
+	}
+
+	// The name tree keeps the names sorted, so the index of the attachment
+	// is found by its name instead of assumed to be the last one.
+	index, err := d.attachmentIndex(ctx, name)
+	if err != nil {
+		if deleteErr := d.deleteAttachmentHandle(ctx, handle); deleteErr != nil {
+			return nil, fmt.Errorf("%w (removing the attachment failed too: %v)", err, deleteErr)
+		}
+		return nil, err
+	}
+
+	if err := d.setAttachment(ctx, handle, name, data, options); err != nil {
+		if deleteErr := d.DeleteAttachment(ctx, index); deleteErr != nil {
+			return nil, fmt.Errorf("%w (removing the attachment failed too: %v)", err, deleteErr)
+		}
+		return nil, err
+	}
+
+	return d.attachment(ctx, handle, index)
+}
+
+// setAttachment sets the file and dates of the new attachment handle.
+func (d *Document) setAttachment(ctx context.Context, handle uint64, name string, data []byte, options AttachmentOptions) error {
+	i := d.instance
+
+	dataPointer, err := i.allocBytes(ctx, data)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, dataPointer)
+
+	success, err := i.call1(ctx, "FPDFAttachment_SetFile", handle, d.handle, dataPointer, uint64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not set the file of attachment %s", name)
+	}
+
+	dates := []struct {
+		key   string
+		value time.Time
+	}{
+		{"CreationDate", options.CreationDate},
+		{"ModDate", options.ModDate},
+	}
+
+	for _, date := range dates {
+		if date.value.IsZero() {
+			continue
+		}
+
+		if err := d.setAttachmentStringValue(ctx, handle, date.key, FormatDate(date.value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachmentIndex returns the index of the attachment with the given name.
+func (d *Document) attachmentIndex(ctx context.Context, name string) (int, error) {
+	count, err := d.AttachmentCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for index := 0; index < count; index++ {
+		handle, err := d.attachmentHandle(ctx, index)
+		if err != nil {
+			return 0, err
+		}
+
+		attachmentName, err := d.instance.callUTF16(ctx, "FPDFAttachment_GetName", handle)
+		if err != nil {
+			return 0, err
+		}
+
+		if attachmentName == name {
+			return index, nil
+		}
+	}
+
+	return 0, fmt.Errorf("pdfium: attachment %s not found", name)
+}
+
+// deleteAttachmentHandle removes the attachment handle, for when its index
+// is not known. FPDFDoc_GetAttachment returns the same file specification
+// dictionary as FPDFDoc_AddAttachment, so the handles can be compared.
+func (d *Document) deleteAttachmentHandle(ctx context.Context, handle uint64) error {
+	count, err := d.AttachmentCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index := 0; index < count; index++ {
+		attachmentHandle, err := d.attachmentHandle(ctx, index)
+		if err != nil {
+			return err
+		}
+
+		if attachmentHandle == handle {
+			return d.DeleteAttachment(ctx, index)
+		}
+	}
+
+	return errors.New("pdfium: attachment to delete not found")
+}
+
+// DeleteAttachment removes the embedded file at index. Only the reference to
+// the file is removed, the data stays in the file until it is rewritten.
+func (d *Document) DeleteAttachment(ctx context.Context, index int) error {
+	success, err := d.instance.call1(ctx, "FPDFDoc_DeleteAttachment", d.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not delete attachment %d", index)
+	}
+
+	return nil
+}
+
+// attachmentHandle returns the FPDF_ATTACHMENT handle at index.
+func (d *Document) attachmentHandle(ctx context.Context, index int) (uint64, error) {
+	handle, err := d.instance.call1(ctx, "FPDFDoc_GetAttachment", d.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return 0, err
+	}
+
+	if handle == 0 {
+		return 0, fmt.Errorf("pdfium: attachment %d not found", index)
+	}
+
+	return handle, nil
+}
+
+// attachmentStringValue returns the value of key in the params dictionary of
+// the attachment handle.
+func (d *Document) attachmentStringValue(ctx context.Context, handle uint64, key string) (string, error) {
+	i := d.instance
+
+	keyPointer, err := i.allocCString(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, keyPointer)
+
+	return i.callUTF16(ctx, "FPDFAttachment_GetStringValue", handle, keyPointer)
+}
+
+// setAttachmentStringValue sets key in the params dictionary of the
+// attachment handle.
+func (d *Document) setAttachmentStringValue(ctx context.Context, handle uint64, key, value string) error {
+	i := d.instance
+
+	keyPointer, err := i.allocCString(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, keyPointer)
+
+	valuePointer, err := i.allocWideString(ctx, value)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, valuePointer)
+
+	success, err := i.call1(ctx, "FPDFAttachment_SetStringValue", handle, keyPointer, valuePointer)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not set %s of attachment", key)
+	}
+
+	return nil
+}
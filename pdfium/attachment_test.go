@@ -0,0 +1,109 @@
+package pdfium
+
+import (
+	"context"
+	"testing"
+)
+
+// deletedAttachment is where the test FPDFDoc_DeleteAttachment stores the
+// index it deleted.
+const deletedAttachment = 0x10000
+
+// newAttachmentDocument returns a document with two attachments, a and b,
+// with handles 100 and 101. Adding an attachment returns handle 101 and
+// setting its file fails.
+func newAttachmentDocument(t *testing.T) *Document {
+	t.Helper()
+
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFDoc_GetAttachmentCount": returnI32(i32s(1), 2),
+		"FPDFDoc_GetAttachment": {
+			params:  i32s(2),
+			results: i32s(1),
+			body:    join(localGet(1), i32Const(100), []byte{opI32Add}),
+		},
+		"FPDFDoc_AddAttachment": returnI32(i32s(2), 101),
+		"FPDFAttachment_GetName": {
+			params:  i32s(3),
+			results: i32s(1),
+			body: join(
+				localGet(1), []byte{opIf, 0x40},
+				localGet(1), localGet(0), i32Const('a'-100), []byte{opI32Add}, memarg(opI32Store, 2, 0),
+				[]byte{opEnd},
+				i32Const(4),
+			),
+		},
+		"FPDFAttachment_GetStringValue": returnI32(i32s(4), 0),
+		"FPDFAttachment_SetFile":        returnI32(i32s(4), 0),
+		"FPDFDoc_DeleteAttachment": {
+			params:  i32s(2),
+			results: i32s(1),
+			body:    join(i32Const(deletedAttachment), localGet(1), memarg(opI32Store, 2, 0), i32Const(1)),
+		},
+	})
+
+	if !i.mod.Memory().WriteUint32Le(context.Background(), deletedAttachment, 0xffffffff) {
+		t.Fatal("could not write the deleted attachment")
+	}
+
+	return &Document{instance: i, handle: 1}
+}
+
+// deletedAttachmentIndex returns the index the document deleted, -1 when none
+// was deleted.
+func deletedAttachmentIndex(t *testing.T, d *Document) int {
+	t.Helper()
+
+	index, ok := d.instance.mod.Memory().ReadUint32Le(context.Background(), deletedAttachment)
+	if !ok {
+		t.Fatal("could not read the deleted attachment")
+	}
+
+	return int(int32(index))
+}
+
+func TestAttachments(t *testing.T) {
+	d := newAttachmentDocument(t)
+
+	attachments, err := d.Attachments(context.Background())
+	if err != nil {
+		t.Fatalf("Attachments() error: %v", err)
+	}
+
+	if len(attachments) != 2 {
+		t.Fatalf("Attachments() returned %d attachments, want 2", len(attachments))
+	}
+
+	for index, name := range []string{"a", "b"} {
+		attachment := attachments[index]
+		if attachment.Index != index || attachment.Name != name || attachment.Subtype != "" || attachment.CheckSum != "" || !attachment.CreationDate.IsZero() {
+			t.Errorf("attachment %d = %+v, want index %d and name %q", index, attachment, index, name)
+		}
+	}
+}
+
+func TestAddAttachmentSetFileFails(t *testing.T) {
+	d := newAttachmentDocument(t)
+
+	if _, err := d.AddAttachment(context.Background(), "b", []byte("data"), AttachmentOptions{}); err == nil {
+		t.Fatal("AddAttachment() error = nil, want an error")
+	}
+
+	if index := deletedAttachmentIndex(t, d); index != 1 {
+		t.Errorf("AddAttachment() deleted attachment %d, want 1", index)
+	}
+}
+
+func TestAddAttachmentNotFound(t *testing.T) {
+	d := newAttachmentDocument(t)
+
+	// None of the attachments is named c, so the new one is looked up by its
+	// handle to remove it.
+	if _, err := d.AddAttachment(context.Background(), "c", []byte("data"), AttachmentOptions{}); err == nil {
+		t.Fatal("AddAttachment() error = nil, want an error")
+	}
+
+	if index := deletedAttachmentIndex(t, d); index != 1 {
+		t.Errorf("AddAttachment() deleted attachment %d, want 1", index)
+	}
+}
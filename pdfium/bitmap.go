@@ -0,0 +1,142 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// BitmapFormat is the pixel format of a Bitmap.
+type BitmapFormat int
+
+const (
+	BitmapFormatGray BitmapFormat = 1 // FPDFBitmap_Gray, 1 byte per pixel.
+	BitmapFormatBGR  BitmapFormat = 2 // FPDFBitmap_BGR, 3 bytes per pixel.
+	BitmapFormatBGRx BitmapFormat = 3 // FPDFBitmap_BGRx, 4 bytes per pixel, the last byte is unused.
+	BitmapFormatBGRA BitmapFormat = 4 // FPDFBitmap_BGRA, 4 bytes per pixel.
+)
+
+// BytesPerPixel returns the number of bytes a single pixel takes.
+func (f BitmapFormat) BytesPerPixel() int {
+	switch f {
+	case BitmapFormatGray:
+		return 1
+	case BitmapFormatBGR:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Bitmap is a PDFium bitmap backed by a buffer in linear memory.
+type Bitmap struct {
+	instance *Instance
+	handle   uint64
+
+	width  int
+	height int
+	stride int
+	format BitmapFormat
+
+	bufferPointer uint64
+}
+
+// NewBitmap allocates a buffer in linear memory and creates a bitmap of
+// width x height pixels on top of it.
+func (i *Instance) NewBitmap(ctx context.Context, width, height int, format BitmapFormat) (*Bitmap, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("pdfium: invalid bitmap size %dx%d", width, height)
+	}
+
+	stride := width * format.BytesPerPixel()
+
+	bufferPointer, err := i.alloc(ctx, uint64(stride*height))
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := i.call1(ctx, "FPDFBitmap_CreateEx",
+		api.EncodeI32(int32(width)),
+		api.EncodeI32(int32(height)),
+		api.EncodeI32(int32(format)),
+		bufferPointer,
+		api.EncodeI32(int32(stride)),
+	)
+	if err != nil {
+		i.release(ctx, bufferPointer)
+		return nil, err
+	}
+
+	if handle == 0 {
+		i.release(ctx, bufferPointer)
+		return nil, errors.New("pdfium: bitmap could not be created")
+	}
+
+	return &Bitmap{
+		instance:      i,
+		handle:        handle,
+		width:         width,
+		height:        height,
+		stride:        stride,
+		format:        format,
+		bufferPointer: bufferPointer,
+	}, nil
+}
+
+// Width returns the width of the bitmap in pixels.
+func (b *Bitmap) Width() int {
+	return b.width
+}
+
+// Height returns the height of the bitmap in pixels.
+func (b *Bitmap) Height() int {
+	return b.height
+}
+
+// Stride returns the number of bytes per row of the bitmap.
+func (b *Bitmap) Stride() int {
+	return b.stride
+}
+
+// Format returns the pixel format of the bitmap.
+func (b *Bitmap) Format() BitmapFormat {
+	return b.format
+}
+
+// FillRect fills a rectangle of the bitmap with color, given as 0xAARRGGBB.
+func (b *Bitmap) FillRect(ctx context.Context, left, top, width, height int, color uint32) error {
+	_, err := b.instance.call(ctx, "FPDFBitmap_FillRect",
+		b.handle,
+		api.EncodeI32(int32(left)),
+		api.EncodeI32(int32(top)),
+		api.EncodeI32(int32(width)),
+		api.EncodeI32(int32(height)),
+		api.EncodeU32(color),
+	)
+
+	return err
+}
+
+// Bytes returns a copy of the pixel buffer of the bitmap.
+func (b *Bitmap) Bytes(ctx context.Context) ([]byte, error) {
+	return b.instance.read(ctx, b.bufferPointer, uint64(b.stride*b.height))
+}
+
+// Destroy destroys the bitmap and releases its buffer.
+func (b *Bitmap) Destroy(ctx context.Context) error {
+	if b.handle == 0 {
+		return errors.New("pdfium: bitmap is already destroyed")
+	}
+
+	_, err := b.instance.call(ctx, "FPDFBitmap_Destroy", b.handle)
+	b.handle = 0
+
+	if releaseErr := b.instance.release(ctx, b.bufferPointer); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	b.bufferPointer = 0
+
+	return err
+}
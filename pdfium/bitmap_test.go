@@ -0,0 +1,71 @@
+package pdfium
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBitmapFormatBytesPerPixel(t *testing.T) {
+	tests := []struct {
+		format BitmapFormat
+		want   int
+	}{
+		{format: BitmapFormatGray, want: 1},
+		{format: BitmapFormatBGR, want: 3},
+		{format: BitmapFormatBGRx, want: 4},
+		{format: BitmapFormatBGRA, want: 4},
+	}
+
+	for _, test := range tests {
+		if got := test.format.BytesPerPixel(); got != test.want {
+			t.Errorf("BitmapFormat(%d).BytesPerPixel() = %d, want %d", test.format, got, test.want)
+		}
+	}
+}
+
+func TestNewBitmap(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFBitmap_CreateEx": returnI32(i32s(5), 100),
+		"FPDFBitmap_Destroy":  {params: i32s(1)},
+	})
+
+	bitmap, err := i.NewBitmap(ctx, 10, 5, BitmapFormatBGR)
+	if err != nil {
+		t.Fatalf("NewBitmap() error: %v", err)
+	}
+
+	if bitmap.Width() != 10 || bitmap.Height() != 5 || bitmap.Stride() != 30 || bitmap.Format() != BitmapFormatBGR {
+		t.Errorf("NewBitmap() = %dx%d with stride %d and format %d, want 10x5 with stride 30 and format %d",
+			bitmap.Width(), bitmap.Height(), bitmap.Stride(), bitmap.Format(), BitmapFormatBGR)
+	}
+
+	if data, err := bitmap.Bytes(ctx); err != nil || len(data) != 150 {
+		t.Errorf("Bytes() = %d bytes, %v, want 150 bytes", len(data), err)
+	}
+
+	if err := bitmap.Destroy(ctx); err != nil {
+		t.Errorf("Destroy() error: %v", err)
+	}
+
+	if err := bitmap.Destroy(ctx); err == nil {
+		t.Error("second Destroy() succeeded, want an error")
+	}
+}
+
+func TestNewBitmapErrors(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFBitmap_CreateEx": returnI32(i32s(5), 0),
+	})
+
+	for _, size := range [][2]int{{0, 10}, {10, 0}, {-1, 10}} {
+		if _, err := i.NewBitmap(ctx, size[0], size[1], BitmapFormatBGRA); err == nil {
+			t.Errorf("NewBitmap(%d, %d) succeeded, want an error", size[0], size[1])
+		}
+	}
+
+	if _, err := i.NewBitmap(ctx, 10, 10, BitmapFormatBGRA); err == nil {
+		t.Error("NewBitmap() succeeded when PDFium could not create the bitmap, want an error")
+	}
+}
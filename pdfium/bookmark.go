@@ -0,0 +1,141 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxBookmarkDepth limits how deep Bookmarks descends into the outline.
+const maxBookmarkDepth = 256
+
+// ErrBookmarkCycle is returned when the outline of a document is malformed
+// and refers back to a bookmark that was already visited.
+var ErrBookmarkCycle = errors.New("pdfium: bookmark outline contains a cycle")
+
+// ErrBookmarkTooDeep is returned when the outline of a document is nested
+// deeper than Bookmarks descends.
+var ErrBookmarkTooDeep = errors.New("pdfium: bookmark outline is nested too deep")
+
+// Bookmark is an entry in the outline of a document.
+type Bookmark struct {
+	Title string
+
+	// Destination is the target of the bookmark, from either its destination
+	// or its go to action. It is nil when the bookmark has neither.
+	Destination *Destination
+
+	// ActionType is the type of the action of the bookmark.
+	// ActionTypeUnsupported when it has no action.
+	ActionType ActionType
+
+	// URI is the target of a URI action.
+	URI string
+
+	Children []Bookmark
+}
+
+// Bookmarks returns the outline of the document as a tree. When the outline
+// contains a cycle, the bookmarks read up to the repeated one are returned
+// together with ErrBookmarkCycle.
+func (d *Document) Bookmarks(ctx context.Context) ([]Bookmark, error) {
+	return d.bookmarkChildren(ctx, 0, map[uint64]bool{}, 0)
+}
+
+// bookmarkChildren returns the children of the bookmark handle parent, 0 for
+// the root of the outline.
+func (d *Document) bookmarkChildren(ctx context.Context, parent uint64, visited map[uint64]bool, depth int) ([]Bookmark, error) {
+	if depth > maxBookmarkDepth {
+		return nil, fmt.Errorf("%w: more than %d levels", ErrBookmarkTooDeep, maxBookmarkDepth)
+	}
+
+	i := d.instance
+
+	handle, err := i.call1(ctx, "FPDFBookmark_GetFirstChild", d.handle, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []Bookmark
+	for handle != 0 {
+		if visited[handle] {
+			return bookmarks, ErrBookmarkCycle
+		}
+		visited[handle] = true
+
+		bookmark, err := d.bookmark(ctx, handle)
+		if err != nil {
+			return nil, err
+		}
+
+		bookmark.Children, err = d.bookmarkChildren(ctx, handle, visited, depth+1)
+		if errors.Is(err, ErrBookmarkCycle) {
+			return append(bookmarks, *bookmark), err
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		bookmarks = append(bookmarks, *bookmark)
+
+		if handle, err = i.call1(ctx, "FPDFBookmark_GetNextSibling", d.handle, handle); err != nil {
+			return nil, err
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// bookmark reads the bookmark handle, without its children.
+func (d *Document) bookmark(ctx context.Context, handle uint64) (*Bookmark, error) {
+	i := d.instance
+
+	title, err := i.callUTF16(ctx, "FPDFBookmark_GetTitle", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark := &Bookmark{
+		Title: title,
+	}
+
+	dest, err := i.call1(ctx, "FPDFBookmark_GetDest", d.handle, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if dest != 0 {
+		if bookmark.Destination, err = d.destination(ctx, dest); err != nil {
+			return nil, err
+		}
+	}
+
+	action, err := i.call1(ctx, "FPDFBookmark_GetAction", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if action == 0 {
+		return bookmark, nil
+	}
+
+	if bookmark.ActionType, err = d.actionType(ctx, action); err != nil {
+		return nil, err
+	}
+
+	switch bookmark.ActionType {
+	case ActionTypeGoTo:
+		if bookmark.Destination == nil {
+			if bookmark.Destination, err = d.actionDestination(ctx, action); err != nil {
+				return nil, err
+			}
+		}
+	case ActionTypeURI:
+		if bookmark.URI, err = d.actionURI(ctx, action); err != nil {
+			return nil, err
+		}
+	}
+
+	return bookmark, nil
+}
@@ -0,0 +1,140 @@
+package pdfium
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// outlineTable is where the test outline keeps the first child and the next
+// sibling of every bookmark handle.
+const outlineTable = 0x10000
+
+// newOutlineDocument returns a document whose outline is given by links,
+// which maps a bookmark handle, 0 for the root, to its first child and next
+// sibling. The title of a bookmark is the letter at its handle, 1 is A.
+func newOutlineDocument(t *testing.T, links map[uint32][2]uint32) *Document {
+	t.Helper()
+
+	// Loads the entry at offset of the handle in parameter n.
+	loadLink := func(n int, offset uint64) []byte {
+		return join(localGet(n), i32Const(3), []byte{opI32Shl}, memarg(opI32Load, 2, outlineTable+offset))
+	}
+
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFBookmark_GetFirstChild":  {params: i32s(2), results: i32s(1), body: loadLink(1, 0)},
+		"FPDFBookmark_GetNextSibling": {params: i32s(2), results: i32s(1), body: loadLink(1, 4)},
+		"FPDFBookmark_GetTitle": {
+			params:  i32s(3),
+			results: i32s(1),
+			body: join(
+				localGet(1), []byte{opIf, 0x40},
+				localGet(1), localGet(0), i32Const('A'-1), []byte{opI32Add}, memarg(opI32Store, 2, 0),
+				[]byte{opEnd},
+				i32Const(4),
+			),
+		},
+		"FPDFBookmark_GetDest":   returnI32(i32s(2), 0),
+		"FPDFBookmark_GetAction": returnI32(i32s(1), 0),
+	})
+
+	ctx := context.Background()
+	for handle, link := range links {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint32(data, link[0])
+		binary.LittleEndian.PutUint32(data[4:], link[1])
+		if err := i.write(ctx, outlineTable+uint64(handle)*8, data); err != nil {
+			t.Fatalf("write() error: %v", err)
+		}
+	}
+
+	return &Document{instance: i, handle: 1}
+}
+
+// outlineString formats bookmarks like "A(B C) D".
+func outlineString(bookmarks []Bookmark) string {
+	var parts []string
+	for _, bookmark := range bookmarks {
+		part := bookmark.Title
+		if len(bookmark.Children) > 0 {
+			part += "(" + outlineString(bookmark.Children) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestBookmarks(t *testing.T) {
+	tests := []struct {
+		name  string
+		links map[uint32][2]uint32
+		want  string
+	}{
+		{name: "empty", links: nil, want: ""},
+		{name: "flat", links: map[uint32][2]uint32{0: {1, 0}, 1: {0, 2}, 2: {0, 3}}, want: "A B C"},
+		{
+			name:  "nested",
+			links: map[uint32][2]uint32{0: {1, 0}, 1: {2, 3}, 2: {0, 4}, 3: {5, 0}, 5: {6, 0}},
+			want:  "A(B D) C(E(F))",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := newOutlineDocument(t, test.links)
+
+			bookmarks, err := document.Bookmarks(context.Background())
+			if err != nil {
+				t.Fatalf("Bookmarks() error: %v", err)
+			}
+
+			if got := outlineString(bookmarks); got != test.want {
+				t.Errorf("Bookmarks() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBookmarksCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		links map[uint32][2]uint32
+		want  string
+	}{
+		{name: "sibling", links: map[uint32][2]uint32{0: {1, 0}, 1: {0, 2}, 2: {0, 1}}, want: "A B"},
+		{name: "child", links: map[uint32][2]uint32{0: {1, 0}, 1: {2, 0}, 2: {1, 0}}, want: "A(B)"},
+		{name: "itself", links: map[uint32][2]uint32{0: {1, 0}, 1: {0, 1}}, want: "A"},
+		{name: "nested", links: map[uint32][2]uint32{0: {1, 0}, 1: {2, 3}, 2: {4, 1}, 3: {0, 0}, 4: {0, 0}}, want: "A(B(D))"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := newOutlineDocument(t, test.links)
+
+			bookmarks, err := document.Bookmarks(context.Background())
+			if !errors.Is(err, ErrBookmarkCycle) {
+				t.Errorf("Bookmarks() error = %v, want ErrBookmarkCycle", err)
+			}
+
+			if got := outlineString(bookmarks); got != test.want {
+				t.Errorf("Bookmarks() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBookmarksTooDeep(t *testing.T) {
+	// Every bookmark is the only child of the one before it.
+	links := map[uint32][2]uint32{}
+	for handle := uint32(0); handle <= maxBookmarkDepth+1; handle++ {
+		links[handle] = [2]uint32{handle + 1, 0}
+	}
+
+	document := newOutlineDocument(t, links)
+
+	if _, err := document.Bookmarks(context.Background()); !errors.Is(err, ErrBookmarkTooDeep) {
+		t.Errorf("Bookmarks() error = %v, want ErrBookmarkTooDeep", err)
+	}
+}
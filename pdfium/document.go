@@ -0,0 +1,198 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"jerbob92/go-pdfium-wasm/imports"
+)
+
+// Document is a PDF document loaded into an Instance.
+type Document struct {
+	instance *Instance
+	handle   uint64
+
+	// dataPointer is the linear memory that holds the file data of a document
+	// loaded from memory. PDFium reads from it until the document is closed.
+	dataPointer uint64
+
+	// fileAccessPointer is the FPDF_FILEACCESS struct of a document loaded
+	// from a reader. PDFium reads blocks through it until the document is
+	// closed.
+	fileAccessPointer uint64
+
+	// form is the form fill environment, nil until it is first needed.
+	form *formFillEnvironment
+}
+
+// LoadMemDocument loads a document from data. The data is copied into linear
+// memory and released when the document is closed. Password may be empty for
+// documents that are not encrypted, a missing or wrong password results in
+// ErrPassword.
+func (i *Instance) LoadMemDocument(ctx context.Context, data []byte, password string) (*Document, error) {
+	dataPointer, err := i.allocBytes(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordPointer, err := i.allocPassword(ctx, password)
+	if err != nil {
+		i.release(ctx, dataPointer)
+		return nil, err
+	}
+	defer i.release(ctx, passwordPointer)
+
+	handle, err := i.call1(ctx, "FPDF_LoadMemDocument", dataPointer, uint64(len(data)), passwordPointer)
+	if err != nil {
+		i.release(ctx, dataPointer)
+		return nil, err
+	}
+
+	if handle == 0 {
+		i.release(ctx, dataPointer)
+		return nil, i.lastError(ctx)
+	}
+
+	return &Document{
+		instance:    i,
+		handle:      handle,
+		dataPointer: dataPointer,
+	}, nil
+}
+
+// LoadDocument loads a document from path in the filesystem of the instance,
+// see Config.FS. Password is handled like in LoadMemDocument.
+func (i *Instance) LoadDocument(ctx context.Context, path string, password string) (*Document, error) {
+	pathPointer, err := i.allocCString(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pathPointer)
+
+	passwordPointer, err := i.allocPassword(ctx, password)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, passwordPointer)
+
+	handle, err := i.call1(ctx, "FPDF_LoadDocument", pathPointer, passwordPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, i.lastError(ctx)
+	}
+
+	return &Document{
+		instance: i,
+		handle:   handle,
+	}, nil
+}
+
+// LoadCustomDocument loads a document of size bytes from reader. Only the
+// blocks PDFium needs are read, on demand, so the file never has to be in
+// memory as a whole. The reader must stay valid until the document is closed.
+// Password is handled like in LoadMemDocument.
+func (i *Instance) LoadCustomDocument(ctx context.Context, reader io.ReaderAt, size int64, password string) (*Document, error) {
+	// FPDF_FILEACCESS uses an unsigned long for the length, which is 32 bits
+	// in wasm32.
+	if size < 0 || size > math.MaxUint32 {
+		return nil, fmt.Errorf("pdfium: invalid document size %d", size)
+	}
+
+	// FPDF_FILEACCESS_Create is added to the wasm build by
+	// pdfium-binaries.patch. It allocates an FPDF_FILEACCESS whose m_GetBlock
+	// is the FPDF_FILEACCESS_CB import, with the struct itself as param.
+	fileAccessPointer, err := i.call1(ctx, "FPDF_FILEACCESS_Create", uint64(size))
+	if err != nil {
+		return nil, err
+	}
+
+	if fileAccessPointer == 0 {
+		return nil, errors.New("pdfium: could not create file access")
+	}
+
+	passwordPointer, err := i.allocPassword(ctx, password)
+	if err != nil {
+		i.release(ctx, fileAccessPointer)
+		return nil, err
+	}
+	defer i.release(ctx, passwordPointer)
+
+	imports.RegisterFileReader(i.mod, uint32(fileAccessPointer), reader)
+
+	handle, err := i.call1(ctx, "FPDF_LoadCustomDocument", fileAccessPointer, passwordPointer)
+	if err == nil && handle == 0 {
+		err = i.lastError(ctx)
+	}
+
+	if err != nil {
+		imports.UnregisterFileReader(i.mod, uint32(fileAccessPointer))
+		i.release(ctx, fileAccessPointer)
+		return nil, err
+	}
+
+	return &Document{
+		instance:          i,
+		handle:            handle,
+		fileAccessPointer: fileAccessPointer,
+	}, nil
+}
+
+// allocPassword copies password into linear memory. An empty password is
+// passed to PDFium as a null pointer.
+func (i *Instance) allocPassword(ctx context.Context, password string) (uint64, error) {
+	if password == "" {
+		return 0, nil
+	}
+
+	return i.allocCString(ctx, password)
+}
+
+// PageCount returns the number of pages in the document.
+func (d *Document) PageCount(ctx context.Context) (int, error) {
+	count, err := d.instance.call1(ctx, "FPDF_GetPageCount", d.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(int32(count)), nil
+}
+
+// Close closes the document and releases its memory. Pages loaded from the
+// document must be closed first.
+func (d *Document) Close(ctx context.Context) error {
+	if d.handle == 0 {
+		return errors.New("pdfium: document is already closed")
+	}
+
+	var err error
+	if d.form != nil {
+		err = d.form.close(ctx)
+		d.form = nil
+	}
+
+	if _, closeErr := d.instance.call(ctx, "FPDF_CloseDocument", d.handle); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	d.handle = 0
+
+	if releaseErr := d.instance.release(ctx, d.dataPointer); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	d.dataPointer = 0
+
+	if d.fileAccessPointer != 0 {
+		imports.UnregisterFileReader(d.instance.mod, uint32(d.fileAccessPointer))
+		if releaseErr := d.instance.release(ctx, d.fileAccessPointer); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+		d.fileAccessPointer = 0
+	}
+
+	return err
+}
@@ -0,0 +1,98 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLoadMemDocument(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_LoadMemDocument": returnI32(i32s(3), 200),
+		"FPDF_GetPageCount":    returnI32(i32s(1), 3),
+		"FPDF_CloseDocument":   {params: i32s(1)},
+	})
+
+	document, err := i.LoadMemDocument(ctx, []byte("%PDF-1.7"), "")
+	if err != nil {
+		t.Fatalf("LoadMemDocument() error: %v", err)
+	}
+
+	if count, err := document.PageCount(ctx); err != nil || count != 3 {
+		t.Errorf("PageCount() = %d, %v, want 3", count, err)
+	}
+
+	if err := document.Close(ctx); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+
+	if err := document.Close(ctx); err == nil {
+		t.Error("second Close() succeeded, want an error")
+	}
+}
+
+func TestLoadMemDocumentFails(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_LoadMemDocument": returnI32(i32s(3), 0),
+		"FPDF_GetLastError":    returnI32(nil, 3),
+	})
+
+	_, err := i.LoadMemDocument(context.Background(), []byte("not a PDF"), "")
+	if !errors.Is(err, ErrFormat) {
+		t.Errorf("LoadMemDocument() error = %v, want ErrFormat", err)
+	}
+}
+
+func TestLoadCustomDocumentInvalidSize(t *testing.T) {
+	for _, size := range []int64{-1, math.MaxUint32 + 1} {
+		_, err := (&Instance{}).LoadCustomDocument(context.Background(), strings.NewReader(""), size, "")
+		if err == nil || !strings.Contains(err.Error(), "invalid document size") {
+			t.Errorf("LoadCustomDocument() of size %d error = %v, want an invalid size error", size, err)
+		}
+	}
+}
+
+func TestLoadCustomDocumentFails(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_FILEACCESS_Create":  returnI32(i32s(1), 2048),
+		"FPDF_LoadCustomDocument": returnI32(i32s(2), 0),
+		"FPDF_GetLastError":       returnI32(nil, 4),
+	})
+
+	_, err := i.LoadCustomDocument(context.Background(), strings.NewReader("%PDF-1.7"), 8, "wrong")
+	if !errors.Is(err, ErrPassword) {
+		t.Errorf("LoadCustomDocument() error = %v, want ErrPassword", err)
+	}
+}
+
+func TestLoadCustomDocumentWithoutFileAccess(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_FILEACCESS_Create": returnI32(i32s(1), 0),
+	})
+
+	_, err := i.LoadCustomDocument(context.Background(), strings.NewReader("%PDF-1.7"), 8, "")
+	if err == nil || !strings.Contains(err.Error(), "could not create file access") {
+		t.Errorf("LoadCustomDocument() error = %v, want a file access error", err)
+	}
+}
+
+func TestAllocPassword(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, nil)
+
+	if pointer, err := i.allocPassword(ctx, ""); err != nil || pointer != 0 {
+		t.Errorf("allocPassword(\"\") = %d, %v, want a null pointer", pointer, err)
+	}
+
+	pointer, err := i.allocPassword(ctx, "secret")
+	if err != nil {
+		t.Fatalf("allocPassword() error: %v", err)
+	}
+
+	if data, _ := i.read(ctx, pointer, 7); string(data) != "secret\x00" {
+		t.Errorf("allocPassword() wrote %q, want a NUL terminated password", data)
+	}
+}
@@ -0,0 +1,405 @@
+package pdfium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+
+	"jerbob92/go-pdfium-wasm/imports"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// TextObject is text to add to a page with Page.AddText.
+type TextObject struct {
+	Text string
+
+	// Font is the name of one of the standard 14 fonts, like Helvetica or
+	// Times-Bold. Defaults to Helvetica.
+	Font string
+
+	// FontSize is the size of the font in points.
+	FontSize float64
+
+	// Position is the origin of the first character in page coordinates.
+	Position Point
+
+	// Color defaults to opaque black.
+	Color color.Color
+}
+
+// RectObject is a rectangle to add to a page with Page.AddRect.
+type RectObject struct {
+	Rect Rect
+
+	// FillColor fills the rectangle, it is not filled when it is nil.
+	FillColor color.Color
+
+	// StrokeColor outlines the rectangle with StrokeWidth, it is not outlined
+	// when it is nil.
+	StrokeColor color.Color
+	StrokeWidth float64
+}
+
+// NewDocument creates an empty document, without pages.
+func (i *Instance) NewDocument(ctx context.Context) (*Document, error) {
+	handle, err := i.call1(ctx, "FPDF_CreateNewDocument")
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, errors.New("pdfium: could not create document")
+	}
+
+	return &Document{
+		instance: i,
+		handle:   handle,
+	}, nil
+}
+
+// NewPage inserts an empty page of width x height points at index and loads
+// it. An index past the last page appends the page. Pages that are already
+// loaded keep their Index, which is stale after inserting a page before them,
+// so append instead while pages are loaded.
+func (d *Document) NewPage(ctx context.Context, index int, width, height float64) (*Page, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("pdfium: invalid page size %gx%g", width, height)
+	}
+
+	pageCount, err := d.PageCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 {
+		return nil, fmt.Errorf("%w: index %d", ErrPage, index)
+	}
+
+	if index > pageCount {
+		index = pageCount
+	}
+
+	handle, err := d.instance.call1(ctx, "FPDFPage_New", d.handle, api.EncodeI32(int32(index)), api.EncodeF64(width), api.EncodeF64(height))
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, fmt.Errorf("pdfium: could not create page %d", index)
+	}
+
+	if d.form != nil {
+		if _, err := d.instance.call(ctx, "FORM_OnAfterLoadPage", handle, d.form.handle); err != nil {
+			d.instance.call(ctx, "FPDF_ClosePage", handle)
+			return nil, err
+		}
+	}
+
+	return &Page{
+		document: d,
+		handle:   handle,
+		index:    index,
+	}, nil
+}
+
+// DeletePage removes the page at index from the document. The page must not
+// be loaded.
+func (d *Document) DeletePage(ctx context.Context, index int) error {
+	pageCount, err := d.PageCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	if index < 0 || index >= pageCount {
+		return fmt.Errorf("%w: index %d", ErrPage, index)
+	}
+
+	_, err = d.instance.call(ctx, "FPDFPage_Delete", d.handle, api.EncodeI32(int32(index)))
+	return err
+}
+
+// GenerateContent writes the objects added to the page to its content
+// stream. It must be called before the document is saved for the changes
+// to be kept.
+func (p *Page) GenerateContent(ctx context.Context) error {
+	success, err := p.document.instance.call1(ctx, "FPDFPage_GenerateContent", p.handle)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not generate content of page %d", p.index)
+	}
+
+	return nil
+}
+
+// AddText adds a line of text to the page.
+func (p *Page) AddText(ctx context.Context, text TextObject) error {
+	i := p.document.instance
+
+	font := text.Font
+	if font == "" {
+		font = "Helvetica"
+	}
+
+	if text.FontSize <= 0 {
+		return fmt.Errorf("pdfium: invalid font size %g", text.FontSize)
+	}
+
+	fontPointer, err := i.allocCString(ctx, font)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, fontPointer)
+
+	object, err := i.call1(ctx, "FPDFPageObj_NewTextObj", p.document.handle, fontPointer, api.EncodeF32(float32(text.FontSize)))
+	if err != nil {
+		return err
+	}
+
+	if object == 0 {
+		return fmt.Errorf("pdfium: could not create text object with font %s", font)
+	}
+
+	return p.insertObject(ctx, object, func() error {
+		textPointer, err := i.allocWideString(ctx, text.Text)
+		if err != nil {
+			return err
+		}
+		defer i.release(ctx, textPointer)
+
+		success, err := i.call1(ctx, "FPDFText_SetText", object, textPointer)
+		if err != nil {
+			return err
+		}
+
+		if success == 0 {
+			return errors.New("pdfium: could not set text")
+		}
+
+		textColor := text.Color
+		if textColor == nil {
+			textColor = color.Black
+		}
+
+		if err := p.setObjectColor(ctx, "FPDFPageObj_SetFillColor", object, textColor); err != nil {
+			return err
+		}
+
+		return p.transformObject(ctx, object, Matrix{A: 1, D: 1, E: text.Position.X, F: text.Position.Y})
+	})
+}
+
+// AddRect adds a rectangle to the page.
+func (p *Page) AddRect(ctx context.Context, rect RectObject) error {
+	i := p.document.instance
+
+	object, err := i.call1(ctx, "FPDFPageObj_CreateNewRect",
+		api.EncodeF32(float32(rect.Rect.Left)),
+		api.EncodeF32(float32(rect.Rect.Bottom)),
+		api.EncodeF32(float32(rect.Rect.Right-rect.Rect.Left)),
+		api.EncodeF32(float32(rect.Rect.Top-rect.Rect.Bottom)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if object == 0 {
+		return errors.New("pdfium: could not create rect object")
+	}
+
+	return p.insertObject(ctx, object, func() error {
+		// FPDF_FILLMODE_NONE or FPDF_FILLMODE_WINDING.
+		fillMode := uint64(0)
+		if rect.FillColor != nil {
+			fillMode = 2
+			if err := p.setObjectColor(ctx, "FPDFPageObj_SetFillColor", object, rect.FillColor); err != nil {
+				return err
+			}
+		}
+
+		stroke := uint64(0)
+		if rect.StrokeColor != nil {
+			stroke = 1
+			if err := p.setObjectColor(ctx, "FPDFPageObj_SetStrokeColor", object, rect.StrokeColor); err != nil {
+				return err
+			}
+
+			success, err := i.call1(ctx, "FPDFPageObj_SetStrokeWidth", object, api.EncodeF32(float32(rect.StrokeWidth)))
+			if err != nil {
+				return err
+			}
+
+			if success == 0 {
+				return fmt.Errorf("pdfium: invalid stroke width %g", rect.StrokeWidth)
+			}
+		}
+
+		success, err := i.call1(ctx, "FPDFPath_SetDrawMode", object, fillMode, stroke)
+		if err != nil {
+			return err
+		}
+
+		if success == 0 {
+			return errors.New("pdfium: could not set draw mode")
+		}
+
+		return nil
+	})
+}
+
+// AddImage adds img to the page, scaled to fill rect. The image is stored
+// uncompressed, use AddJPEG for JPEG files.
+func (p *Page) AddImage(ctx context.Context, img image.Image, rect Rect) error {
+	i := p.document.instance
+
+	bounds := img.Bounds()
+	bitmap, err := i.NewBitmap(ctx, bounds.Dx(), bounds.Dy(), BitmapFormatBGRA)
+	if err != nil {
+		return err
+	}
+	defer bitmap.Destroy(ctx)
+
+	pix := make([]byte, bitmap.stride*bitmap.height)
+	for y := 0; y < bitmap.height; y++ {
+		for x := 0; x < bitmap.width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			offset := y*bitmap.stride + x*4
+			pix[offset], pix[offset+1], pix[offset+2], pix[offset+3] = c.B, c.G, c.R, c.A
+		}
+	}
+
+	if err := i.write(ctx, bitmap.bufferPointer, pix); err != nil {
+		return err
+	}
+
+	object, err := i.call1(ctx, "FPDFPageObj_NewImageObj", p.document.handle)
+	if err != nil {
+		return err
+	}
+
+	if object == 0 {
+		return errors.New("pdfium: could not create image object")
+	}
+
+	return p.insertObject(ctx, object, func() error {
+		// The bitmap is copied into the image object.
+		success, err := i.call1(ctx, "FPDFImageObj_SetBitmap", 0, 0, object, bitmap.handle)
+		if err != nil {
+			return err
+		}
+
+		if success == 0 {
+			return errors.New("pdfium: could not set image bitmap")
+		}
+
+		return p.transformObject(ctx, object, rectMatrix(rect))
+	})
+}
+
+// AddJPEG adds the JPEG file in data to the page, scaled to fill rect. The
+// file is embedded as is, without decoding it.
+func (p *Page) AddJPEG(ctx context.Context, data []byte, rect Rect) error {
+	i := p.document.instance
+
+	object, err := i.call1(ctx, "FPDFPageObj_NewImageObj", p.document.handle)
+	if err != nil {
+		return err
+	}
+
+	if object == 0 {
+		return errors.New("pdfium: could not create image object")
+	}
+
+	return p.insertObject(ctx, object, func() error {
+		// See LoadCustomDocument for FPDF_FILEACCESS_Create.
+		fileAccessPointer, err := i.call1(ctx, "FPDF_FILEACCESS_Create", uint64(len(data)))
+		if err != nil {
+			return err
+		}
+
+		if fileAccessPointer == 0 {
+			return errors.New("pdfium: could not create file access")
+		}
+		defer i.release(ctx, fileAccessPointer)
+
+		// The inline variant reads the whole file right away, so the reader is
+		// not needed after the call.
+		imports.RegisterFileReader(i.mod, uint32(fileAccessPointer), bytes.NewReader(data))
+		defer imports.UnregisterFileReader(i.mod, uint32(fileAccessPointer))
+
+		success, err := i.call1(ctx, "FPDFImageObj_LoadJpegFileInline", 0, 0, object, fileAccessPointer)
+		if err != nil {
+			return err
+		}
+
+		if success == 0 {
+			return errors.New("pdfium: could not load JPEG")
+		}
+
+		return p.transformObject(ctx, object, rectMatrix(rect))
+	})
+}
+
+// insertObject calls fn to set up the FPDF_PAGEOBJECT handle and inserts it
+// into the page, or destroys it when fn fails.
+func (p *Page) insertObject(ctx context.Context, object uint64, fn func() error) error {
+	i := p.document.instance
+
+	if err := fn(); err != nil {
+		i.call(ctx, "FPDFPageObj_Destroy", object)
+		return err
+	}
+
+	// The page owns the object from here on.
+	_, err := i.call(ctx, "FPDFPage_InsertObject", p.handle, object)
+	return err
+}
+
+// setObjectColor calls the FPDFPageObj_Set*Color function with the given
+// name for the FPDF_PAGEOBJECT handle.
+func (p *Page) setObjectColor(ctx context.Context, name string, object uint64, c color.Color) error {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	success, err := p.document.instance.call1(ctx, name, object, uint64(n.R), uint64(n.G), uint64(n.B), uint64(n.A))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not set object color")
+	}
+
+	return nil
+}
+
+// transformObject transforms the FPDF_PAGEOBJECT handle by matrix.
+func (p *Page) transformObject(ctx context.Context, object uint64, matrix Matrix) error {
+	_, err := p.document.instance.call(ctx, "FPDFPageObj_Transform",
+		object,
+		api.EncodeF64(matrix.A),
+		api.EncodeF64(matrix.B),
+		api.EncodeF64(matrix.C),
+		api.EncodeF64(matrix.D),
+		api.EncodeF64(matrix.E),
+		api.EncodeF64(matrix.F),
+	)
+
+	return err
+}
+
+// rectMatrix returns the matrix that maps the unit square of an image to
+// rect.
+func rectMatrix(rect Rect) Matrix {
+	return Matrix{
+		A: rect.Right - rect.Left,
+		D: rect.Top - rect.Bottom,
+		E: rect.Left,
+		F: rect.Bottom,
+	}
+}
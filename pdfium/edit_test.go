@@ -0,0 +1,91 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestRectMatrix(t *testing.T) {
+	m := rectMatrix(Rect{Left: 10, Top: 70, Right: 40, Bottom: 20})
+
+	tests := []struct {
+		point, want Point
+	}{
+		{Point{X: 0, Y: 0}, Point{X: 10, Y: 20}},
+		{Point{X: 1, Y: 0}, Point{X: 40, Y: 20}},
+		{Point{X: 0, Y: 1}, Point{X: 10, Y: 70}},
+		{Point{X: 1, Y: 1}, Point{X: 40, Y: 70}},
+	}
+
+	for _, test := range tests {
+		if got := m.Apply(test.point); got != test.want {
+			t.Errorf("rectMatrix().Apply(%v) = %v, want %v", test.point, got, test.want)
+		}
+	}
+}
+
+// newEditDocument returns a document with two pages, where new pages get
+// handle 5.
+func newEditDocument(t *testing.T) *Document {
+	t.Helper()
+
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_GetPageCount": returnI32(i32s(1), 2),
+		"FPDFPage_New":      returnI32([]api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeF64, api.ValueTypeF64}, 5),
+	})
+
+	return &Document{instance: i, handle: 1}
+}
+
+func TestNewPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		index     int
+		wantIndex int
+	}{
+		{name: "first", index: 0, wantIndex: 0},
+		{name: "append", index: 2, wantIndex: 2},
+		{name: "past the end", index: 10, wantIndex: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := newEditDocument(t).NewPage(context.Background(), test.index, 612, 792)
+			if err != nil {
+				t.Fatalf("NewPage() error: %v", err)
+			}
+
+			if page.handle != 5 || page.index != test.wantIndex {
+				t.Errorf("NewPage() = handle %d, index %d, want 5, %d", page.handle, page.index, test.wantIndex)
+			}
+		})
+	}
+}
+
+func TestNewPageInvalid(t *testing.T) {
+	d := newEditDocument(t)
+	ctx := context.Background()
+
+	if _, err := d.NewPage(ctx, -1, 612, 792); !errors.Is(err, ErrPage) {
+		t.Errorf("NewPage() at index -1 error = %v, want ErrPage", err)
+	}
+
+	for _, size := range [][2]float64{{0, 792}, {612, 0}, {-1, -1}} {
+		if _, err := d.NewPage(ctx, 0, size[0], size[1]); err == nil {
+			t.Errorf("NewPage() of %gx%g error = nil, want an error", size[0], size[1])
+		}
+	}
+}
+
+func TestAddTextInvalidFontSize(t *testing.T) {
+	p := &Page{document: &Document{}}
+
+	for _, size := range []float64{0, -12} {
+		if err := p.AddText(context.Background(), TextObject{Text: "text", FontSize: size}); err == nil {
+			t.Errorf("AddText() with font size %g error = nil, want an error", size)
+		}
+	}
+}
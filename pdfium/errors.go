@@ -0,0 +1,60 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// The errors PDFium reports through FPDF_GetLastError.
+var (
+	ErrUnknown  = errors.New("pdfium: unknown error")
+	ErrFile     = errors.New("pdfium: file not found or could not be opened")
+	ErrFormat   = errors.New("pdfium: file not in PDF format or corrupted")
+	ErrPassword = errors.New("pdfium: password required or incorrect password")
+	ErrSecurity = errors.New("pdfium: unsupported security scheme")
+	ErrPage     = errors.New("pdfium: page not found or content error")
+)
+
+// CallError is returned when calling into the module fails, like on a trap or
+// an abort. The state of the instance can't be trusted afterwards.
+type CallError struct {
+	// Name is the name of the called function.
+	Name string
+	Err  error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("pdfium: could not call %s: %v", e.Name, e.Err)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// lastError returns the error PDFium reported for the last failed call.
+func (i *Instance) lastError(ctx context.Context) error {
+	code, err := i.call1(ctx, "FPDF_GetLastError")
+	if err != nil {
+		return err
+	}
+
+	switch code {
+	case 0: // FPDF_ERR_SUCCESS, the call failed without telling why.
+		return ErrUnknown
+	case 1: // FPDF_ERR_UNKNOWN
+		return ErrUnknown
+	case 2: // FPDF_ERR_FILE
+		return ErrFile
+	case 3: // FPDF_ERR_FORMAT
+		return ErrFormat
+	case 4: // FPDF_ERR_PASSWORD
+		return ErrPassword
+	case 5: // FPDF_ERR_SECURITY
+		return ErrSecurity
+	case 6: // FPDF_ERR_PAGE
+		return ErrPage
+	default:
+		return fmt.Errorf("%w: error code %d", ErrUnknown, code)
+	}
+}
@@ -0,0 +1,48 @@
+package pdfium
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestLastError(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		// Returns the error code stored at address 0.
+		"FPDF_GetLastError": {
+			results: i32s(1),
+			body:    join(i32Const(0), memarg(opI32Load, 2, 0)),
+		},
+	})
+
+	tests := []struct {
+		code uint32
+		want error
+	}{
+		{code: 0, want: ErrUnknown},
+		{code: 1, want: ErrUnknown},
+		{code: 2, want: ErrFile},
+		{code: 3, want: ErrFormat},
+		{code: 4, want: ErrPassword},
+		{code: 5, want: ErrSecurity},
+		{code: 6, want: ErrPage},
+		{code: 1000, want: ErrUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(strconv.Itoa(int(test.code)), func(t *testing.T) {
+			code := make([]byte, 4)
+			binary.LittleEndian.PutUint32(code, test.code)
+			if err := i.write(ctx, 0, code); err != nil {
+				t.Fatalf("write() error: %v", err)
+			}
+
+			if err := i.lastError(ctx); !errors.Is(err, test.want) {
+				t.Errorf("lastError() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
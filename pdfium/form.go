@@ -0,0 +1,209 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+
+	"jerbob92/go-pdfium-wasm/imports"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// formFillEnvironment is the form fill environment of a document. It also
+// handles the callbacks of its FPDF_FORMFILLINFO.
+type formFillEnvironment struct {
+	document *Document
+
+	// handle is the FPDF_FORMHANDLE.
+	handle uint64
+
+	// infoPointer is the FPDF_FORMFILLINFO struct.
+	infoPointer uint64
+
+	// pages are the pages PDFium asked for through FFI_GetPage, by index.
+	pages map[int32]uint64
+}
+
+// formFillInfoVersion is the version of FPDF_FORMFILLINFO, 2 also has the
+// XFA callbacks, which the wasm build does not support.
+const formFillInfoVersion = 1
+
+// formFillEnvironment returns the form fill environment of the document,
+// initializing it the first time.
+func (d *Document) formFillEnvironment(ctx context.Context) (*formFillEnvironment, error) {
+	if d.form != nil {
+		return d.form, nil
+	}
+
+	i := d.instance
+
+	// FPDF_FORMFILLINFO_Create is added to the wasm build by
+	// pdfium-binaries.patch. It allocates an FPDF_FORMFILLINFO whose callbacks
+	// are the FPDF_FORMFILLINFO_FFI_* imports.
+	infoPointer, err := i.call1(ctx, "FPDF_FORMFILLINFO_Create", formFillInfoVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if infoPointer == 0 {
+		return nil, errors.New("pdfium: could not create form fill info")
+	}
+
+	form := &formFillEnvironment{
+		document:    d,
+		infoPointer: infoPointer,
+		pages:       map[int32]uint64{},
+	}
+
+	imports.RegisterFormFillHandler(i.mod, uint32(infoPointer), form)
+
+	handle, err := i.call1(ctx, "FPDFDOC_InitFormFillEnvironment", d.handle, infoPointer)
+	if err == nil && handle == 0 {
+		err = errors.New("pdfium: could not init form fill environment")
+	}
+
+	if err != nil {
+		imports.UnregisterFormFillHandler(i.mod, uint32(infoPointer))
+		i.release(ctx, infoPointer)
+		return nil, err
+	}
+
+	form.handle = handle
+	d.form = form
+
+	return form, nil
+}
+
+// close closes the pages the environment loaded and exits it.
+func (f *formFillEnvironment) close(ctx context.Context) error {
+	i := f.document.instance
+
+	var err error
+	for _, page := range f.pages {
+		if _, closeErr := i.call(ctx, "FORM_OnBeforeClosePage", page, f.handle); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		if _, closeErr := i.call(ctx, "FPDF_ClosePage", page); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	f.pages = nil
+
+	if _, exitErr := i.call(ctx, "FPDFDOC_ExitFormFillEnvironment", f.handle); exitErr != nil && err == nil {
+		err = exitErr
+	}
+
+	imports.UnregisterFormFillHandler(i.mod, uint32(f.infoPointer))
+	if releaseErr := i.release(ctx, f.infoPointer); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+
+	return err
+}
+
+// setHighlight sets or removes the highlight of all form fields, following
+// options.FormFieldHighlightColor.
+func (f *formFillEnvironment) setHighlight(ctx context.Context, options RenderOptions) error {
+	i := f.document.instance
+
+	rgb, alpha, ok := options.formFieldHighlight()
+	if !ok {
+		_, err := i.call(ctx, "FPDF_RemoveFormFieldHighlight", f.handle)
+		return err
+	}
+
+	// Field type 0, FPDF_FORMFIELD_UNKNOWN, sets the color of all types.
+	if _, err := i.call(ctx, "FPDF_SetFormFieldHighlightColor", f.handle, 0, uint64(rgb)); err != nil {
+		return err
+	}
+
+	_, err := i.call(ctx, "FPDF_SetFormFieldHighlightAlpha", f.handle, uint64(alpha))
+	return err
+}
+
+// Invalidate implements imports.FormFillHandler.Invalidate, there is no
+// screen to repaint.
+func (f *formFillEnvironment) Invalidate(ctx context.Context, page uint32, left, top, right, bottom float64) {
+}
+
+// OutputSelectedRect implements imports.FormFillHandler.OutputSelectedRect.
+func (f *formFillEnvironment) OutputSelectedRect(ctx context.Context, page uint32, left, top, right, bottom float64) {
+}
+
+// SetCursor implements imports.FormFillHandler.SetCursor.
+func (f *formFillEnvironment) SetCursor(ctx context.Context, cursorType int32) {
+}
+
+// SetTimer implements imports.FormFillHandler.SetTimer, timers are not
+// supported.
+func (f *formFillEnvironment) SetTimer(ctx context.Context, elapse int32, timerFunc uint32) int32 {
+	return 0
+}
+
+// KillTimer implements imports.FormFillHandler.KillTimer.
+func (f *formFillEnvironment) KillTimer(ctx context.Context, timerID int32) {
+}
+
+// OnChange implements imports.FormFillHandler.OnChange.
+func (f *formFillEnvironment) OnChange(ctx context.Context) {
+}
+
+// GetPage implements imports.FormFillHandler.GetPage. The page is loaded
+// once and kept until the environment is closed.
+func (f *formFillEnvironment) GetPage(ctx context.Context, document uint32, pageIndex int32) uint32 {
+	if uint64(document) != f.document.handle {
+		return 0
+	}
+
+	if page, ok := f.pages[pageIndex]; ok {
+		return uint32(page)
+	}
+
+	i := f.document.instance
+
+	page, err := i.call1(ctx, "FPDF_LoadPage", f.document.handle, api.EncodeI32(pageIndex))
+	if err != nil || page == 0 {
+		return 0
+	}
+
+	if _, err := i.call(ctx, "FORM_OnAfterLoadPage", page, f.handle); err != nil {
+		i.call(ctx, "FPDF_ClosePage", page)
+		return 0
+	}
+
+	f.pages[pageIndex] = page
+
+	return uint32(page)
+}
+
+// GetCurrentPage implements imports.FormFillHandler.GetCurrentPage, there is
+// no current page.
+func (f *formFillEnvironment) GetCurrentPage(ctx context.Context, document uint32) uint32 {
+	return 0
+}
+
+// GetRotation implements imports.FormFillHandler.GetRotation.
+func (f *formFillEnvironment) GetRotation(ctx context.Context, page uint32) int32 {
+	rotation, err := f.document.instance.call1(ctx, "FPDFPage_GetRotation", uint64(page))
+	if err != nil {
+		return 0
+	}
+
+	return int32(rotation)
+}
+
+// ExecuteNamedAction implements imports.FormFillHandler.ExecuteNamedAction.
+func (f *formFillEnvironment) ExecuteNamedAction(ctx context.Context, namedAction string) {
+}
+
+// SetTextFieldFocus implements imports.FormFillHandler.SetTextFieldFocus.
+func (f *formFillEnvironment) SetTextFieldFocus(ctx context.Context, value string, isFocus bool) {
+}
+
+// DoURIAction implements imports.FormFillHandler.DoURIAction.
+func (f *formFillEnvironment) DoURIAction(ctx context.Context, uri string) {
+}
+
+// DoGoToAction implements imports.FormFillHandler.DoGoToAction.
+func (f *formFillEnvironment) DoGoToAction(ctx context.Context, pageIndex int32, zoomMode int32, position []float32) {
+}
@@ -0,0 +1,260 @@
+package pdfium
+
+import (
+	"context"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// FormFieldType is the type of a form field.
+type FormFieldType int
+
+const (
+	FormFieldTypeUnknown     FormFieldType = 0 // FPDF_FORMFIELD_UNKNOWN
+	FormFieldTypePushButton  FormFieldType = 1 // FPDF_FORMFIELD_PUSHBUTTON
+	FormFieldTypeCheckBox    FormFieldType = 2 // FPDF_FORMFIELD_CHECKBOX
+	FormFieldTypeRadioButton FormFieldType = 3 // FPDF_FORMFIELD_RADIOBUTTON
+	FormFieldTypeComboBox    FormFieldType = 4 // FPDF_FORMFIELD_COMBOBOX
+	FormFieldTypeListBox     FormFieldType = 5 // FPDF_FORMFIELD_LISTBOX
+	FormFieldTypeTextField   FormFieldType = 6 // FPDF_FORMFIELD_TEXTFIELD
+	FormFieldTypeSignature   FormFieldType = 7 // FPDF_FORMFIELD_SIGNATURE
+)
+
+// FormFieldFlags are the field flags of a form field, see section 12.7.3.1 of
+// the PDF 1.7 specification.
+type FormFieldFlags int
+
+const (
+	FormFieldFlagReadOnly          FormFieldFlags = 1 << 0  // FPDF_FORMFLAG_READONLY
+	FormFieldFlagRequired          FormFieldFlags = 1 << 1  // FPDF_FORMFLAG_REQUIRED
+	FormFieldFlagNoExport          FormFieldFlags = 1 << 2  // FPDF_FORMFLAG_NOEXPORT
+	FormFieldFlagTextMultiline     FormFieldFlags = 1 << 12 // FPDF_FORMFLAG_TEXT_MULTILINE
+	FormFieldFlagTextPassword      FormFieldFlags = 1 << 13 // FPDF_FORMFLAG_TEXT_PASSWORD
+	FormFieldFlagChoiceCombo       FormFieldFlags = 1 << 17 // FPDF_FORMFLAG_CHOICE_COMBO
+	FormFieldFlagChoiceEdit        FormFieldFlags = 1 << 18 // FPDF_FORMFLAG_CHOICE_EDIT
+	FormFieldFlagChoiceMultiSelect FormFieldFlags = 1 << 21 // FPDF_FORMFLAG_CHOICE_MULTI_SELECT
+)
+
+// FormFieldOption is an option of a combo box or list box.
+type FormFieldOption struct {
+	Label    string
+	Selected bool
+}
+
+// FormWidget is an appearance of a form field on a page. A field can have
+// more than one, like the buttons of a radio button group.
+type FormWidget struct {
+	// PageIndex and AnnotationIndex locate the widget annotation.
+	PageIndex       int
+	AnnotationIndex int
+
+	Rect Rect
+
+	// ExportValue is the value of a check box or radio button when it is
+	// checked.
+	ExportValue string
+
+	// Checked is whether a check box or radio button is checked.
+	Checked bool
+}
+
+// FormField is an interactive form field.
+type FormField struct {
+	// Name is the fully qualified name of the field.
+	Name string
+
+	// AlternateName is the name to show to users, empty when it is not set.
+	AlternateName string
+
+	Type  FormFieldType
+	Value string
+	Flags FormFieldFlags
+
+	// Options are the options of a combo box or list box.
+	Options []FormFieldOption
+
+	Widgets []FormWidget
+}
+
+// annotationSubtypeWidget is FPDF_ANNOT_WIDGET.
+const annotationSubtypeWidget = 20
+
+// FormFields returns the interactive form fields of the document, in the
+// order their first widget appears.
+func (d *Document) FormFields(ctx context.Context) ([]FormField, error) {
+	if _, err := d.formFillEnvironment(ctx); err != nil {
+		return nil, err
+	}
+
+	pageCount, err := d.PageCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []FormField
+	fieldIndexes := map[string]int{}
+
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		err := d.eachWidget(ctx, pageIndex, func(page *Page, annotationIndex int, annotation uint64) error {
+			field, err := d.formField(ctx, annotation)
+			if err != nil {
+				return err
+			}
+
+			widget, err := d.formWidget(ctx, annotation)
+			if err != nil {
+				return err
+			}
+			widget.PageIndex = pageIndex
+			widget.AnnotationIndex = annotationIndex
+
+			index, ok := fieldIndexes[field.Name]
+			if !ok {
+				index = len(fields)
+				fieldIndexes[field.Name] = index
+				fields = append(fields, *field)
+			}
+			fields[index].Widgets = append(fields[index].Widgets, *widget)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+// eachWidget calls fn for every widget annotation on the page at pageIndex.
+// The annotation is closed when fn returns.
+func (d *Document) eachWidget(ctx context.Context, pageIndex int, fn func(page *Page, annotationIndex int, annotation uint64) error) error {
+	i := d.instance
+
+	page, err := d.LoadPage(ctx, pageIndex)
+	if err != nil {
+		return err
+	}
+	defer page.Close(ctx)
+
+	count, err := i.call1(ctx, "FPDFPage_GetAnnotCount", page.handle)
+	if err != nil {
+		return err
+	}
+
+	for annotationIndex := 0; annotationIndex < int(int32(count)); annotationIndex++ {
+		annotation, err := i.call1(ctx, "FPDFPage_GetAnnot", page.handle, api.EncodeI32(int32(annotationIndex)))
+		if err != nil {
+			return err
+		}
+
+		if annotation == 0 {
+			continue
+		}
+
+		err = func() error {
+			defer i.call(ctx, "FPDFPage_CloseAnnot", annotation)
+
+			subtype, err := i.call1(ctx, "FPDFAnnot_GetSubtype", annotation)
+			if err != nil {
+				return err
+			}
+
+			if int32(subtype) != annotationSubtypeWidget {
+				return nil
+			}
+
+			return fn(page, annotationIndex, annotation)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formField reads the field of the widget annotation, without its widgets.
+func (d *Document) formField(ctx context.Context, annotation uint64) (*FormField, error) {
+	i := d.instance
+	form := d.form.handle
+
+	field := &FormField{}
+
+	var err error
+	if field.Name, err = i.callUTF16(ctx, "FPDFAnnot_GetFormFieldName", form, annotation); err != nil {
+		return nil, err
+	}
+
+	if field.AlternateName, err = i.callUTF16(ctx, "FPDFAnnot_GetFormFieldAlternateName", form, annotation); err != nil {
+		return nil, err
+	}
+
+	if field.Value, err = i.callUTF16(ctx, "FPDFAnnot_GetFormFieldValue", form, annotation); err != nil {
+		return nil, err
+	}
+
+	fieldType, err := i.call1(ctx, "FPDFAnnot_GetFormFieldType", form, annotation)
+	if err != nil {
+		return nil, err
+	}
+	field.Type = FormFieldType(int32(fieldType))
+
+	flags, err := i.call1(ctx, "FPDFAnnot_GetFormFieldFlags", form, annotation)
+	if err != nil {
+		return nil, err
+	}
+	field.Flags = FormFieldFlags(int32(flags))
+
+	// The count is -1 for fields that are not a combo box or list box.
+	optionCount, err := i.call1(ctx, "FPDFAnnot_GetOptionCount", form, annotation)
+	if err != nil {
+		return nil, err
+	}
+
+	for optionIndex := 0; optionIndex < int(int32(optionCount)); optionIndex++ {
+		option := FormFieldOption{}
+
+		if option.Label, err = i.callUTF16(ctx, "FPDFAnnot_GetOptionLabel", form, annotation, api.EncodeI32(int32(optionIndex))); err != nil {
+			return nil, err
+		}
+
+		selected, err := i.call1(ctx, "FPDFAnnot_IsOptionSelected", form, annotation, api.EncodeI32(int32(optionIndex)))
+		if err != nil {
+			return nil, err
+		}
+		option.Selected = selected != 0
+
+		field.Options = append(field.Options, option)
+	}
+
+	return field, nil
+}
+
+// formWidget reads the widget of the widget annotation, without its location.
+func (d *Document) formWidget(ctx context.Context, annotation uint64) (*FormWidget, error) {
+	i := d.instance
+	form := d.form.handle
+
+	widget := &FormWidget{}
+
+	values, err := i.callOutFloat32s(ctx, "FPDFAnnot_GetRect", 4, func(pointer uint64) []uint64 {
+		return []uint64{annotation, pointer}
+	})
+	if err != nil {
+		return nil, err
+	}
+	widget.Rect = Rect{Left: float64(values[0]), Top: float64(values[1]), Right: float64(values[2]), Bottom: float64(values[3])}
+
+	if widget.ExportValue, err = i.callUTF16(ctx, "FPDFAnnot_GetFormFieldExportValue", form, annotation); err != nil {
+		return nil, err
+	}
+
+	checked, err := i.call1(ctx, "FPDFAnnot_IsChecked", form, annotation)
+	if err != nil {
+		return nil, err
+	}
+	widget.Checked = checked != 0
+
+	return widget, nil
+}
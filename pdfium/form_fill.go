@@ -0,0 +1,384 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// ErrFormFieldNotFound is returned when a form field to fill does not exist.
+var ErrFormFieldNotFound = errors.New("pdfium: form field not found")
+
+// ErrFormFieldType is returned when a form field is filled with a value its
+// type does not take, like checking a text field.
+var ErrFormFieldType = errors.New("pdfium: wrong form field type")
+
+// FlattenMode selects which appearance of annotations and form fields
+// Flatten burns into the page content.
+type FlattenMode int
+
+const (
+	FlattenModeNormalDisplay FlattenMode = 0 // FLAT_NORMALDISPLAY
+	FlattenModePrint         FlattenMode = 1 // FLAT_PRINT
+)
+
+// SetTextFieldValue replaces the value of the text field or editable combo
+// box with the given name.
+func (d *Document) SetTextFieldValue(ctx context.Context, name string, value string) error {
+	i := d.instance
+
+	types := []FormFieldType{FormFieldTypeTextField, FormFieldTypeComboBox}
+
+	return d.fillWidgets(ctx, name, types, func(page *Page, annotation uint64, form uint64) (bool, error) {
+		if err := d.focusWidget(ctx, annotation); err != nil {
+			return false, err
+		}
+
+		if _, err := i.call(ctx, "FORM_SelectAllText", form, page.handle); err != nil {
+			return false, err
+		}
+
+		valuePointer, err := i.allocWideString(ctx, value)
+		if err != nil {
+			return false, err
+		}
+		defer i.release(ctx, valuePointer)
+
+		if _, err := i.call(ctx, "FORM_ReplaceSelection", form, page.handle, valuePointer); err != nil {
+			return false, err
+		}
+
+		// The value is committed when the field loses focus. Read-only fields
+		// and fields with a maximum length don't take the value as is.
+		if _, err := i.call(ctx, "FORM_ForceToKillFocus", form); err != nil {
+			return false, err
+		}
+
+		current, err := i.callUTF16(ctx, "FPDFAnnot_GetFormFieldValue", form, annotation)
+		if err != nil {
+			return false, err
+		}
+
+		if current != value {
+			return false, fmt.Errorf("pdfium: could not set the value of %s, it is %q", name, current)
+		}
+
+		// Setting one widget sets the value of the whole field.
+		return true, nil
+	})
+}
+
+// SetCheckBoxChecked checks or unchecks the check box with the given name.
+func (d *Document) SetCheckBoxChecked(ctx context.Context, name string, checked bool) error {
+	types := []FormFieldType{FormFieldTypeCheckBox}
+
+	return d.fillWidgets(ctx, name, types, func(page *Page, annotation uint64, form uint64) (bool, error) {
+		if err := d.setWidgetChecked(ctx, page, annotation, checked); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	})
+}
+
+// SetRadioButton checks the button of the radio button group with the given
+// name that has exportValue as its export value.
+func (d *Document) SetRadioButton(ctx context.Context, name string, exportValue string) error {
+	i := d.instance
+
+	types := []FormFieldType{FormFieldTypeRadioButton}
+
+	found := false
+	err := d.fillWidgets(ctx, name, types, func(page *Page, annotation uint64, form uint64) (bool, error) {
+		value, err := i.callUTF16(ctx, "FPDFAnnot_GetFormFieldExportValue", form, annotation)
+		if err != nil {
+			return false, err
+		}
+
+		if value != exportValue {
+			return false, nil
+		}
+
+		found = true
+		if err := d.setWidgetChecked(ctx, page, annotation, true); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s has no button with export value %s", ErrFormFieldNotFound, name, exportValue)
+	}
+
+	return nil
+}
+
+// SetOptionSelected selects or deselects the option with the given label of
+// the combo box or list box with the given name.
+func (d *Document) SetOptionSelected(ctx context.Context, name string, label string, selected bool) error {
+	i := d.instance
+
+	types := []FormFieldType{FormFieldTypeComboBox, FormFieldTypeListBox}
+
+	found := false
+	err := d.fillWidgets(ctx, name, types, func(page *Page, annotation uint64, form uint64) (bool, error) {
+		optionCount, err := i.call1(ctx, "FPDFAnnot_GetOptionCount", form, annotation)
+		if err != nil {
+			return false, err
+		}
+
+		for optionIndex := 0; optionIndex < int(int32(optionCount)); optionIndex++ {
+			optionLabel, err := i.callUTF16(ctx, "FPDFAnnot_GetOptionLabel", form, annotation, api.EncodeI32(int32(optionIndex)))
+			if err != nil {
+				return false, err
+			}
+
+			if optionLabel != label {
+				continue
+			}
+
+			found = true
+
+			if err := d.focusWidget(ctx, annotation); err != nil {
+				return false, err
+			}
+
+			success, err := i.call1(ctx, "FORM_SetIndexSelected", form, page.handle, api.EncodeI32(int32(optionIndex)), boolToUint64(selected))
+			if err != nil {
+				return false, err
+			}
+
+			if success == 0 {
+				return false, fmt.Errorf("pdfium: could not select option %s of %s", label, name)
+			}
+
+			if _, err := i.call(ctx, "FORM_ForceToKillFocus", form); err != nil {
+				return false, err
+			}
+
+			isSelected, err := i.call1(ctx, "FPDFAnnot_IsOptionSelected", form, annotation, api.EncodeI32(int32(optionIndex)))
+			if err != nil {
+				return false, err
+			}
+
+			if (isSelected != 0) != selected {
+				return false, fmt.Errorf("pdfium: could not select option %s of %s", label, name)
+			}
+
+			return true, nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s has no option %s", ErrFormFieldNotFound, name, label)
+	}
+
+	return nil
+}
+
+// fillWidgets calls fn for the widgets of the form field with the given name
+// until it returns true, and removes the focus from the field afterwards. The
+// field must have one of types.
+func (d *Document) fillWidgets(ctx context.Context, name string, types []FormFieldType, fn func(page *Page, annotation uint64, form uint64) (bool, error)) error {
+	form, err := d.formFillEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	pageCount, err := d.PageCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	i := d.instance
+	errDone := errors.New("done")
+
+	found := false
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		err := d.eachWidget(ctx, pageIndex, func(page *Page, annotationIndex int, annotation uint64) error {
+			fieldName, err := i.callUTF16(ctx, "FPDFAnnot_GetFormFieldName", form.handle, annotation)
+			if err != nil {
+				return err
+			}
+
+			if fieldName != name {
+				return nil
+			}
+
+			found = true
+
+			fieldType, err := i.call1(ctx, "FPDFAnnot_GetFormFieldType", form.handle, annotation)
+			if err != nil {
+				return err
+			}
+
+			if !containsFormFieldType(types, FormFieldType(int32(fieldType))) {
+				return fmt.Errorf("%w: %s has type %d", ErrFormFieldType, name, int32(fieldType))
+			}
+
+			done, err := fn(page, annotation, form.handle)
+			if err == nil && done {
+				err = errDone
+			}
+
+			// Commit the change before the page is closed.
+			if _, killErr := i.call(ctx, "FORM_ForceToKillFocus", form.handle); killErr != nil && (err == nil || err == errDone) {
+				err = killErr
+			}
+
+			return err
+		})
+
+		if err == errDone {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s", ErrFormFieldNotFound, name)
+	}
+
+	return nil
+}
+
+// focusWidget gives the widget annotation the focus.
+func (d *Document) focusWidget(ctx context.Context, annotation uint64) error {
+	success, err := d.instance.call1(ctx, "FORM_SetFocusedAnnot", d.form.handle, annotation)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not focus form field")
+	}
+
+	return nil
+}
+
+// setWidgetChecked clicks the check box or radio button widget annotation
+// when its state differs from checked.
+func (d *Document) setWidgetChecked(ctx context.Context, page *Page, annotation uint64, checked bool) error {
+	i := d.instance
+	form := d.form.handle
+
+	isChecked, err := i.call1(ctx, "FPDFAnnot_IsChecked", form, annotation)
+	if err != nil {
+		return err
+	}
+
+	if (isChecked != 0) == checked {
+		return nil
+	}
+
+	values, err := i.callOutFloat32s(ctx, "FPDFAnnot_GetRect", 4, func(pointer uint64) []uint64 {
+		return []uint64{annotation, pointer}
+	})
+	if err != nil {
+		return err
+	}
+
+	x := api.EncodeF64(float64(values[0]+values[2]) / 2)
+	y := api.EncodeF64(float64(values[1]+values[3]) / 2)
+
+	if _, err := i.call(ctx, "FORM_OnLButtonDown", form, page.handle, 0, x, y); err != nil {
+		return err
+	}
+
+	if _, err := i.call(ctx, "FORM_OnLButtonUp", form, page.handle, 0, x, y); err != nil {
+		return err
+	}
+
+	// The click does nothing on read-only or hidden widgets.
+	isChecked, err = i.call1(ctx, "FPDFAnnot_IsChecked", form, annotation)
+	if err != nil {
+		return err
+	}
+
+	if (isChecked != 0) != checked {
+		return errors.New("pdfium: could not change the state of the form field, it may be read-only or hidden")
+	}
+
+	return nil
+}
+
+// Flatten burns the annotations and form fields of the page into its
+// content, so they can no longer be edited. Use Document.Save to write the
+// result.
+func (p *Page) Flatten(ctx context.Context, mode FlattenMode) error {
+	result, err := p.document.instance.call1(ctx, "FPDFPage_Flatten", p.handle, api.EncodeI32(int32(mode)))
+	if err != nil {
+		return err
+	}
+
+	// FLATTEN_FAIL, FLATTEN_SUCCESS and FLATTEN_NOTHINGTODO.
+	if int32(result) == 0 {
+		return fmt.Errorf("pdfium: could not flatten page %d", p.index)
+	}
+
+	return nil
+}
+
+// Flatten flattens every page of the document, see Page.Flatten.
+func (d *Document) Flatten(ctx context.Context, mode FlattenMode) error {
+	if d.form != nil {
+		if _, err := d.instance.call(ctx, "FORM_ForceToKillFocus", d.form.handle); err != nil {
+			return err
+		}
+	}
+
+	pageCount, err := d.PageCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	for pageIndex := 0; pageIndex < pageCount; pageIndex++ {
+		page, err := d.LoadPage(ctx, pageIndex)
+		if err != nil {
+			return err
+		}
+
+		err = page.Flatten(ctx, mode)
+		if closeErr := page.Close(ctx); closeErr != nil && err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func containsFormFieldType(types []FormFieldType, fieldType FormFieldType) bool {
+	for _, t := range types {
+		if t == fieldType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func boolToUint64(b bool) uint64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
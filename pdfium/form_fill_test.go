@@ -0,0 +1,35 @@
+package pdfium
+
+import "testing"
+
+func TestContainsFormFieldType(t *testing.T) {
+	types := []FormFieldType{FormFieldTypeTextField, FormFieldTypeComboBox}
+
+	tests := []struct {
+		fieldType FormFieldType
+		want      bool
+	}{
+		{FormFieldTypeTextField, true},
+		{FormFieldTypeComboBox, true},
+		{FormFieldTypeCheckBox, false},
+	}
+
+	for _, test := range tests {
+		if got := containsFormFieldType(types, test.fieldType); got != test.want {
+			t.Errorf("containsFormFieldType(%v) = %v, want %v", test.fieldType, got, test.want)
+		}
+	}
+
+	if containsFormFieldType(nil, FormFieldTypeTextField) {
+		t.Error("containsFormFieldType(nil) = true, want false")
+	}
+}
+
+func TestBoolToUint64(t *testing.T) {
+	if got := boolToUint64(true); got != 1 {
+		t.Errorf("boolToUint64(true) = %d, want 1", got)
+	}
+	if got := boolToUint64(false); got != 0 {
+		t.Errorf("boolToUint64(false) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,86 @@
+package pdfium
+
+import (
+	"context"
+	"testing"
+)
+
+// pageLoads is where the test FPDF_LoadPage counts how often it was called.
+const pageLoads = 0x10000
+
+func TestFormFillEnvironmentGetPage(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_FORMFILLINFO_Create":        returnI32(i32s(1), 0x8000),
+		"FPDFDOC_InitFormFillEnvironment": returnI32(i32s(2), 7),
+		"FPDFDOC_ExitFormFillEnvironment": {params: i32s(1)},
+		"FPDF_LoadPage": {
+			params:  i32s(2),
+			results: i32s(1),
+			body: join(
+				i32Const(pageLoads), i32Const(pageLoads), memarg(opI32Load, 2, 0), i32Const(1), []byte{opI32Add}, memarg(opI32Store, 2, 0),
+				localGet(1), i32Const(10), []byte{opI32Add},
+			),
+		},
+		"FPDF_ClosePage":         {params: i32s(1)},
+		"FORM_OnAfterLoadPage":   {params: i32s(2)},
+		"FORM_OnBeforeClosePage": {params: i32s(2)},
+		"FPDFPage_GetRotation":   returnI32(i32s(1), 3),
+	})
+	ctx := context.Background()
+	d := &Document{instance: i, handle: 1}
+
+	form, err := d.formFillEnvironment(ctx)
+	if err != nil {
+		t.Fatalf("formFillEnvironment() error: %v", err)
+	}
+
+	if form.handle != 7 || form.infoPointer != 0x8000 {
+		t.Errorf("formFillEnvironment() = handle %d, info %#x, want 7, 0x8000", form.handle, form.infoPointer)
+	}
+
+	if again, err := d.formFillEnvironment(ctx); err != nil || again != form {
+		t.Errorf("formFillEnvironment() again = %p, %v, want the same environment", again, err)
+	}
+
+	if page := form.GetPage(ctx, 2, 0); page != 0 {
+		t.Errorf("GetPage() of another document = %d, want 0", page)
+	}
+
+	for n := 0; n < 2; n++ {
+		if page := form.GetPage(ctx, 1, 3); page != 13 {
+			t.Errorf("GetPage() = %d, want 13", page)
+		}
+	}
+
+	if loads, _ := i.mod.Memory().ReadUint32Le(ctx, pageLoads); loads != 1 {
+		t.Errorf("FPDF_LoadPage called %d times, want 1", loads)
+	}
+
+	if rotation := form.GetRotation(ctx, 13); rotation != 3 {
+		t.Errorf("GetRotation() = %d, want 3", rotation)
+	}
+
+	if err := form.close(ctx); err != nil {
+		t.Errorf("close() error: %v", err)
+	}
+
+	if form.pages != nil {
+		t.Errorf("close() kept pages %v", form.pages)
+	}
+}
+
+func TestFormFillEnvironmentInitFails(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_FORMFILLINFO_Create":        returnI32(i32s(1), 0x8000),
+		"FPDFDOC_InitFormFillEnvironment": returnI32(i32s(2), 0),
+	})
+	d := &Document{instance: i, handle: 1}
+
+	if _, err := d.formFillEnvironment(context.Background()); err == nil {
+		t.Fatal("formFillEnvironment() error = nil, want an error")
+	}
+
+	if d.form != nil {
+		t.Error("formFillEnvironment() kept the environment")
+	}
+}
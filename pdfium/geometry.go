@@ -0,0 +1,61 @@
+package pdfium
+
+import "math"
+
+// Point is a point in page coordinates, in points with the origin at the
+// bottom left of the page.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Rect is a rectangle in page coordinates.
+type Rect struct {
+	Left   float64
+	Top    float64
+	Right  float64
+	Bottom float64
+}
+
+// Union returns the smallest rectangle that contains both r and other.
+func (r Rect) Union(other Rect) Rect {
+	return Rect{
+		Left:   math.Min(r.Left, other.Left),
+		Top:    math.Max(r.Top, other.Top),
+		Right:  math.Max(r.Right, other.Right),
+		Bottom: math.Min(r.Bottom, other.Bottom),
+	}
+}
+
+// Quad is a quadrilateral in page coordinates, like the area of a text
+// markup annotation. The points are in the order of the PDF QuadPoints array.
+type Quad [4]Point
+
+// Bounds returns the smallest rectangle that contains the quadrilateral.
+func (q Quad) Bounds() Rect {
+	rect := Rect{Left: q[0].X, Top: q[0].Y, Right: q[0].X, Bottom: q[0].Y}
+	for _, point := range q[1:] {
+		rect = rect.Union(Rect{Left: point.X, Top: point.Y, Right: point.X, Bottom: point.Y})
+	}
+
+	return rect
+}
+
+// Matrix is a transformation matrix, it maps x, y to
+// A*x + C*y + E, B*x + D*y + F.
+type Matrix struct {
+	A float64
+	B float64
+	C float64
+	D float64
+	E float64
+	F float64
+}
+
+// Apply transforms point by the matrix.
+func (m Matrix) Apply(point Point) Point {
+	return Point{
+		X: m.A*point.X + m.C*point.Y + m.E,
+		Y: m.B*point.X + m.D*point.Y + m.F,
+	}
+}
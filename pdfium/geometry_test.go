@@ -0,0 +1,105 @@
+package pdfium
+
+import "testing"
+
+func TestRectUnion(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rect
+		o    Rect
+		want Rect
+	}{
+		{
+			name: "disjoint",
+			r:    Rect{Left: 0, Top: 10, Right: 10, Bottom: 0},
+			o:    Rect{Left: 20, Top: 30, Right: 30, Bottom: 20},
+			want: Rect{Left: 0, Top: 30, Right: 30, Bottom: 0},
+		},
+		{
+			name: "overlapping",
+			r:    Rect{Left: 0, Top: 10, Right: 10, Bottom: 0},
+			o:    Rect{Left: 5, Top: 15, Right: 15, Bottom: 5},
+			want: Rect{Left: 0, Top: 15, Right: 15, Bottom: 0},
+		},
+		{
+			name: "contained",
+			r:    Rect{Left: 0, Top: 10, Right: 10, Bottom: 0},
+			o:    Rect{Left: 2, Top: 8, Right: 8, Bottom: 2},
+			want: Rect{Left: 0, Top: 10, Right: 10, Bottom: 0},
+		},
+		{
+			name: "negative",
+			r:    Rect{Left: -5, Top: -1, Right: -2, Bottom: -4},
+			o:    Rect{Left: 1, Top: 2, Right: 3, Bottom: 1},
+			want: Rect{Left: -5, Top: 2, Right: 3, Bottom: -4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.r.Union(test.o); got != test.want {
+				t.Errorf("%v.Union(%v) = %v, want %v", test.r, test.o, got, test.want)
+			}
+
+			if got := test.o.Union(test.r); got != test.want {
+				t.Errorf("%v.Union(%v) = %v, want %v", test.o, test.r, got, test.want)
+			}
+		})
+	}
+}
+
+func TestQuadBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		quad Quad
+		want Rect
+	}{
+		{
+			name: "upright",
+			quad: Quad{{X: 10, Y: 20}, {X: 50, Y: 20}, {X: 10, Y: 5}, {X: 50, Y: 5}},
+			want: Rect{Left: 10, Top: 20, Right: 50, Bottom: 5},
+		},
+		{
+			name: "rotated",
+			quad: Quad{{X: 0, Y: 5}, {X: 5, Y: 10}, {X: 5, Y: 0}, {X: 10, Y: 5}},
+			want: Rect{Left: 0, Top: 10, Right: 10, Bottom: 0},
+		},
+		{
+			name: "point",
+			quad: Quad{{X: 3, Y: 4}, {X: 3, Y: 4}, {X: 3, Y: 4}, {X: 3, Y: 4}},
+			want: Rect{Left: 3, Top: 4, Right: 3, Bottom: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.quad.Bounds(); got != test.want {
+				t.Errorf("%v.Bounds() = %v, want %v", test.quad, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatrixApply(t *testing.T) {
+	point := Point{X: 2, Y: 3}
+
+	tests := []struct {
+		name   string
+		matrix Matrix
+		want   Point
+	}{
+		{name: "identity", matrix: Matrix{A: 1, D: 1}, want: Point{X: 2, Y: 3}},
+		{name: "translate", matrix: Matrix{A: 1, D: 1, E: 10, F: -5}, want: Point{X: 12, Y: -2}},
+		{name: "scale", matrix: Matrix{A: 2, D: 0.5}, want: Point{X: 4, Y: 1.5}},
+		{name: "rotate 90", matrix: Matrix{B: 1, C: -1}, want: Point{X: -3, Y: 2}},
+		{name: "shear", matrix: Matrix{A: 1, C: 1, D: 1}, want: Point{X: 5, Y: 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.matrix.Apply(point); got != test.want {
+				t.Errorf("%v.Apply(%v) = %v, want %v", test.matrix, point, got, test.want)
+			}
+		})
+	}
+}
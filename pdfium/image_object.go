@@ -0,0 +1,282 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// ImageColorspace is the colorspace of an image.
+type ImageColorspace int
+
+const (
+	ImageColorspaceUnknown    ImageColorspace = 0  // FPDF_COLORSPACE_UNKNOWN
+	ImageColorspaceDeviceGray ImageColorspace = 1  // FPDF_COLORSPACE_DEVICEGRAY
+	ImageColorspaceDeviceRGB  ImageColorspace = 2  // FPDF_COLORSPACE_DEVICERGB
+	ImageColorspaceDeviceCMYK ImageColorspace = 3  // FPDF_COLORSPACE_DEVICECMYK
+	ImageColorspaceCalGray    ImageColorspace = 4  // FPDF_COLORSPACE_CALGRAY
+	ImageColorspaceCalRGB     ImageColorspace = 5  // FPDF_COLORSPACE_CALRGB
+	ImageColorspaceLab        ImageColorspace = 6  // FPDF_COLORSPACE_LAB
+	ImageColorspaceICCBased   ImageColorspace = 7  // FPDF_COLORSPACE_ICCBASED
+	ImageColorspaceSeparation ImageColorspace = 8  // FPDF_COLORSPACE_SEPARATION
+	ImageColorspaceDeviceN    ImageColorspace = 9  // FPDF_COLORSPACE_DEVICEN
+	ImageColorspaceIndexed    ImageColorspace = 10 // FPDF_COLORSPACE_INDEXED
+	ImageColorspacePattern    ImageColorspace = 11 // FPDF_COLORSPACE_PATTERN
+)
+
+// ImageMetadata describes the image of an image object.
+type ImageMetadata struct {
+	// Width and Height are the size of the image in pixels.
+	Width  int
+	Height int
+
+	// HorizontalDPI and VerticalDPI are the resolution of the image as it is
+	// placed on the page.
+	HorizontalDPI float64
+	VerticalDPI   float64
+
+	BitsPerPixel int
+	Colorspace   ImageColorspace
+
+	// MarkedContentID is the marked content ID of the image, -1 when it has
+	// none.
+	MarkedContentID int
+}
+
+// ImageFilters returns the names of the filters of the image object, like
+// DCTDecode for a JPEG image, in the order they are applied when decoding.
+func (p *Page) ImageFilters(ctx context.Context, object PageObject) ([]string, error) {
+	i := p.document.instance
+
+	handle, err := p.imageObjectHandle(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := i.call1(ctx, "FPDFImageObj_GetImageFilterCount", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []string
+	for index := 0; index < int(int32(count)); index++ {
+		data, err := p.callImageData(ctx, "FPDFImageObj_GetImageFilter", handle, api.EncodeI32(int32(index)))
+		if err != nil {
+			return nil, err
+		}
+
+		// The name is NUL terminated.
+		if len(data) > 0 && data[len(data)-1] == 0 {
+			data = data[:len(data)-1]
+		}
+		filters = append(filters, string(data))
+	}
+
+	return filters, nil
+}
+
+// ImageRawData returns the data of the image object as it is stored in the
+// document, still encoded with its filters. For a DCTDecode or JPXDecode
+// image it is the JPEG or JPEG 2000 file.
+func (p *Page) ImageRawData(ctx context.Context, object PageObject) ([]byte, error) {
+	handle, err := p.imageObjectHandle(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.callImageData(ctx, "FPDFImageObj_GetImageDataRaw", handle)
+}
+
+// ImageDecodedData returns the data of the image object decoded with its
+// filters. Image filters like DCTDecode are not decoded, use RenderImage
+// for the pixels.
+func (p *Page) ImageDecodedData(ctx context.Context, object PageObject) ([]byte, error) {
+	handle, err := p.imageObjectHandle(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.callImageData(ctx, "FPDFImageObj_GetImageDataDecoded", handle)
+}
+
+// ImageMetadata returns the metadata of the image object.
+func (p *Page) ImageMetadata(ctx context.Context, object PageObject) (*ImageMetadata, error) {
+	i := p.document.instance
+
+	handle, err := p.imageObjectHandle(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	// An FPDF_IMAGEOBJ_METADATA, seven 4 byte fields.
+	pointer, err := i.alloc(ctx, 28)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, "FPDFImageObj_GetImageMetadata", handle, p.handle, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if success == 0 {
+		return nil, fmt.Errorf("pdfium: could not get metadata of image %v", object.Path)
+	}
+
+	ints, err := i.readInt32s(ctx, pointer, 7)
+	if err != nil {
+		return nil, err
+	}
+
+	dpi, err := i.readFloat32s(ctx, pointer+8, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageMetadata{
+		Width:           int(uint32(ints[0])),
+		Height:          int(uint32(ints[1])),
+		HorizontalDPI:   float64(dpi[0]),
+		VerticalDPI:     float64(dpi[1]),
+		BitsPerPixel:    int(uint32(ints[4])),
+		Colorspace:      ImageColorspace(ints[5]),
+		MarkedContentID: int(ints[6]),
+	}, nil
+}
+
+// RenderImage renders the image object as it appears on the page, with its
+// mask and matrix applied.
+func (p *Page) RenderImage(ctx context.Context, object PageObject) (image.Image, error) {
+	i := p.document.instance
+
+	handle, err := p.imageObjectHandle(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap, err := i.call1(ctx, "FPDFImageObj_GetRenderedBitmap", p.document.handle, p.handle, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if bitmap == 0 {
+		return nil, fmt.Errorf("pdfium: could not render image %v", object.Path)
+	}
+	defer i.call(ctx, "FPDFBitmap_Destroy", bitmap)
+
+	return i.bitmapImage(ctx, bitmap)
+}
+
+// imageObjectHandle returns the FPDF_PAGEOBJECT handle of object, which must
+// be an image object.
+func (p *Page) imageObjectHandle(ctx context.Context, object PageObject) (uint64, error) {
+	handle, err := p.pageObjectHandle(ctx, object.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	objectType, err := p.document.instance.call1(ctx, "FPDFPageObj_GetType", handle)
+	if err != nil {
+		return 0, err
+	}
+
+	if PageObjectType(int32(objectType)) != PageObjectTypeImage {
+		return 0, fmt.Errorf("pdfium: page object %v is not an image", object.Path)
+	}
+
+	return handle, nil
+}
+
+// callImageData calls the PDFium function with the given name, that writes
+// data to a buffer given as its last two parameters and returns its length.
+// It is first called without a buffer to get the length.
+func (p *Page) callImageData(ctx context.Context, name string, params ...uint64) ([]byte, error) {
+	i := p.document.instance
+
+	length, err := i.call1(ctx, name, append(params[:len(params):len(params)], 0, 0)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint32(length) == 0 {
+		return nil, nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, uint64(uint32(length)))
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, name, append(params[:len(params):len(params)], bufferPointer, uint64(uint32(length)))...); err != nil {
+		return nil, err
+	}
+
+	return i.read(ctx, bufferPointer, uint64(uint32(length)))
+}
+
+// bitmapImage copies the pixels of the FPDF_BITMAP handle to an image.Gray for
+// gray bitmaps or an image.RGBA for the others.
+func (i *Instance) bitmapImage(ctx context.Context, bitmap uint64) (image.Image, error) {
+	values := make([]int, 4)
+	for n, name := range []string{"FPDFBitmap_GetWidth", "FPDFBitmap_GetHeight", "FPDFBitmap_GetStride", "FPDFBitmap_GetFormat"} {
+		value, err := i.call1(ctx, name, bitmap)
+		if err != nil {
+			return nil, err
+		}
+		values[n] = int(int32(value))
+	}
+	width, height, stride, format := values[0], values[1], values[2], BitmapFormat(values[3])
+
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("pdfium: invalid bitmap size %dx%d", width, height)
+	}
+
+	bufferPointer, err := i.call1(ctx, "FPDFBitmap_GetBuffer", bitmap)
+	if err != nil {
+		return nil, err
+	}
+
+	if bufferPointer == 0 {
+		return nil, errors.New("pdfium: bitmap has no buffer")
+	}
+
+	pix, err := i.view(ctx, bufferPointer, uint64(stride*height))
+	if err != nil {
+		return nil, err
+	}
+
+	rect := image.Rect(0, 0, width, height)
+
+	if format == BitmapFormatGray {
+		img := image.NewGray(rect)
+		for y := 0; y < height; y++ {
+			copy(img.Pix[y*img.Stride:y*img.Stride+width], pix[y*stride:])
+		}
+		return img, nil
+	}
+
+	img := image.NewRGBA(rect)
+	bytesPerPixel := format.BytesPerPixel()
+	for y := 0; y < height; y++ {
+		row := pix[y*stride : y*stride+width*bytesPerPixel]
+		dst := img.Pix[y*img.Stride : y*img.Stride+width*4]
+
+		switch format {
+		case BitmapFormatBGRA:
+			bgraToRGBA(dst, row)
+		default:
+			for x := 0; x < width; x++ {
+				b, g, r := row[x*bytesPerPixel], row[x*bytesPerPixel+1], row[x*bytesPerPixel+2]
+				img.SetRGBA(x, y, color.RGBA{R: r, G: g, B: b, A: 0xff})
+			}
+		}
+	}
+
+	return img, nil
+}
@@ -0,0 +1,85 @@
+package pdfium
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// bitmapBuffer is where the pixels of the test bitmaps are.
+const bitmapBuffer = 0x10000
+
+// newBitmapInstance returns an instance whose bitmaps are 2x2 pixels in the
+// given format, with rows of stride bytes at bitmapBuffer.
+func newBitmapInstance(t *testing.T, format BitmapFormat, stride int32, pix []byte) *Instance {
+	t.Helper()
+
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFBitmap_GetWidth":  returnI32(i32s(1), 2),
+		"FPDFBitmap_GetHeight": returnI32(i32s(1), 2),
+		"FPDFBitmap_GetStride": returnI32(i32s(1), stride),
+		"FPDFBitmap_GetFormat": returnI32(i32s(1), int32(format)),
+		"FPDFBitmap_GetBuffer": returnI32(i32s(1), bitmapBuffer),
+	})
+
+	if err := i.write(context.Background(), bitmapBuffer, pix); err != nil {
+		t.Fatalf("write() error: %v", err)
+	}
+
+	return i
+}
+
+func TestBitmapImageGray(t *testing.T) {
+	// Rows are padded to 4 bytes.
+	i := newBitmapInstance(t, BitmapFormatGray, 4, []byte{1, 2, 0xee, 0xee, 3, 4, 0xee, 0xee})
+
+	img, err := i.bitmapImage(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("bitmapImage() error: %v", err)
+	}
+
+	want := &image.Gray{Pix: []byte{1, 2, 3, 4}, Stride: 2, Rect: image.Rect(0, 0, 2, 2)}
+	if !reflect.DeepEqual(img, want) {
+		t.Errorf("bitmapImage() = %v, want %v", img, want)
+	}
+}
+
+func TestBitmapImageBGR(t *testing.T) {
+	// Rows are padded to 8 bytes.
+	i := newBitmapInstance(t, BitmapFormatBGR, 8, []byte{
+		1, 2, 3, 4, 5, 6, 0xee, 0xee,
+		7, 8, 9, 10, 11, 12, 0xee, 0xee,
+	})
+
+	img, err := i.bitmapImage(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("bitmapImage() error: %v", err)
+	}
+
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		t.Fatalf("bitmapImage() = %T, want *image.RGBA", img)
+	}
+
+	want := []color.RGBA{{3, 2, 1, 0xff}, {6, 5, 4, 0xff}, {9, 8, 7, 0xff}, {12, 11, 10, 0xff}}
+	for n, c := range want {
+		if got := rgba.RGBAAt(n%2, n/2); got != c {
+			t.Errorf("pixel %d,%d = %v, want %v", n%2, n/2, got, c)
+		}
+	}
+}
+
+func TestBitmapImageInvalidSize(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFBitmap_GetWidth":  returnI32(i32s(1), 0),
+		"FPDFBitmap_GetHeight": returnI32(i32s(1), 2),
+		"FPDFBitmap_GetStride": returnI32(i32s(1), 0),
+		"FPDFBitmap_GetFormat": returnI32(i32s(1), int32(BitmapFormatBGRA)),
+	})
+
+	if _, err := i.bitmapImage(context.Background(), 1); err == nil {
+		t.Error("bitmapImage() error = nil, want an error")
+	}
+}
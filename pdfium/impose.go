@@ -0,0 +1,256 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// PaperSize is the size of a sheet of paper in points.
+type PaperSize struct {
+	Width  float64
+	Height float64
+}
+
+// Common paper sizes.
+var (
+	PaperA3      = PaperSize{Width: 841.89, Height: 1190.55}
+	PaperA4      = PaperSize{Width: 595.28, Height: 841.89}
+	PaperA5      = PaperSize{Width: 419.53, Height: 595.28}
+	PaperLetter  = PaperSize{Width: 612, Height: 792}
+	PaperLegal   = PaperSize{Width: 612, Height: 1008}
+	PaperTabloid = PaperSize{Width: 792, Height: 1224}
+)
+
+// Landscape returns the paper size with the longest side as width.
+func (s PaperSize) Landscape() PaperSize {
+	if s.Height > s.Width {
+		return PaperSize{Width: s.Height, Height: s.Width}
+	}
+
+	return s
+}
+
+// Portrait returns the paper size with the longest side as height.
+func (s PaperSize) Portrait() PaperSize {
+	if s.Width > s.Height {
+		return PaperSize{Width: s.Height, Height: s.Width}
+	}
+
+	return s
+}
+
+// Imposition is a way to lay out the pages of a document on sheets.
+type Imposition int
+
+const (
+	// Imposition2Up places two pages side by side on each sheet.
+	Imposition2Up Imposition = 1
+
+	// Imposition4Up places four pages in a 2x2 grid on each sheet.
+	Imposition4Up Imposition = 2
+
+	// ImpositionBooklet places two pages side by side on each side of a
+	// sheet, ordered so the printed sheets can be folded into a booklet. The
+	// sheets are printed double sided, flipping on the short edge.
+	ImpositionBooklet Imposition = 3
+)
+
+// Impose creates a document with the pages of doc laid out on sheets of
+// paper. For 2-up and booklets the paper is usually landscape.
+func (i *Instance) Impose(ctx context.Context, doc *Document, imposition Imposition, paper PaperSize) (*Document, error) {
+	switch imposition {
+	case Imposition2Up:
+		return i.NUp(ctx, doc, 2, 1, paper)
+	case Imposition4Up:
+		return i.NUp(ctx, doc, 2, 2, paper)
+	case ImpositionBooklet:
+		return i.booklet(ctx, doc, paper)
+	}
+
+	return nil, fmt.Errorf("pdfium: invalid imposition %d", imposition)
+}
+
+// NUp creates a document with the pages of doc in a grid of columns x rows
+// on each sheet of paper. Pages are scaled to fit their cell, keeping their
+// aspect ratio.
+func (i *Instance) NUp(ctx context.Context, doc *Document, columns, rows int, paper PaperSize) (*Document, error) {
+	if doc.instance != i {
+		return nil, errOtherInstance
+	}
+
+	if columns < 1 || rows < 1 {
+		return nil, fmt.Errorf("pdfium: invalid grid %dx%d", columns, rows)
+	}
+
+	if paper.Width <= 0 || paper.Height <= 0 {
+		return nil, fmt.Errorf("pdfium: invalid paper size %gx%g", paper.Width, paper.Height)
+	}
+
+	handle, err := i.call1(ctx, "FPDF_ImportNPagesToOne",
+		doc.handle,
+		api.EncodeF32(float32(paper.Width)),
+		api.EncodeF32(float32(paper.Height)),
+		uint64(columns),
+		uint64(rows),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, errors.New("pdfium: could not import pages")
+	}
+
+	return &Document{
+		instance: i,
+		handle:   handle,
+	}, nil
+}
+
+// booklet creates a document with the pages of doc laid out as a booklet.
+// The number of pages is padded with blank pages to a multiple of four.
+func (i *Instance) booklet(ctx context.Context, doc *Document, paper PaperSize) (*Document, error) {
+	if paper.Width <= 0 || paper.Height <= 0 {
+		return nil, fmt.Errorf("pdfium: invalid paper size %gx%g", paper.Width, paper.Height)
+	}
+
+	pageCount, err := doc.PageCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pageCount == 0 {
+		return nil, errors.New("pdfium: document has no pages")
+	}
+
+	return i.newDocumentFrom(ctx, doc, func(booklet *Document) error {
+		for sheet, sides := range bookletSheets(pageCount) {
+			for side, pages := range sides {
+				err := booklet.addSheet(ctx, 2*sheet+side, paper, func(page *Page) error {
+					for half, pageIndex := range pages {
+						if pageIndex >= pageCount {
+							continue
+						}
+
+						cell := Rect{
+							Left:   float64(half) * paper.Width / 2,
+							Top:    paper.Height,
+							Right:  float64(half+1) * paper.Width / 2,
+							Bottom: 0,
+						}
+
+						if err := page.placePage(ctx, doc, pageIndex, cell); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// bookletSheets returns the page indexes on the front and back of every sheet
+// of a booklet of pageCount pages, each side with a left and right page. The
+// pages are padded to a multiple of four, indexes of pageCount and up are
+// blank.
+func bookletSheets(pageCount int) [][2][2]int {
+	padded := (pageCount + 3) / 4 * 4
+
+	sheets := make([][2][2]int, padded/4)
+	for sheet := range sheets {
+		sheets[sheet] = [2][2]int{
+			{padded - 1 - 2*sheet, 2 * sheet},
+			{2*sheet + 1, padded - 2 - 2*sheet},
+		}
+	}
+
+	return sheets
+}
+
+// addSheet inserts a page of paper size at index and calls fn to fill it.
+func (d *Document) addSheet(ctx context.Context, index int, paper PaperSize, fn func(sheet *Page) error) error {
+	sheet, err := d.NewPage(ctx, index, paper.Width, paper.Height)
+	if err != nil {
+		return err
+	}
+
+	err = fn(sheet)
+	if err == nil {
+		err = sheet.GenerateContent(ctx)
+	}
+
+	if closeErr := sheet.Close(ctx); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// placePage draws the page at pageIndex of src on the page as a form XObject,
+// scaled to fit cell and centered in it.
+func (p *Page) placePage(ctx context.Context, src *Document, pageIndex int, cell Rect) error {
+	i := p.document.instance
+
+	if src.instance != i {
+		return errOtherInstance
+	}
+
+	size, err := i.callOutFloat32s(ctx, "FPDF_GetPageSizeByIndexF", 2, func(pointer uint64) []uint64 {
+		return []uint64{src.handle, api.EncodeI32(int32(pageIndex)), pointer}
+	})
+	if isFalse(err) {
+		return fmt.Errorf("%w: index %d", ErrPage, pageIndex)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	width, height := float64(size[0]), float64(size[1])
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("pdfium: invalid page size %gx%g", width, height)
+	}
+
+	cellWidth, cellHeight := cell.Right-cell.Left, cell.Top-cell.Bottom
+	scale := math.Min(cellWidth/width, cellHeight/height)
+
+	xobject, err := i.call1(ctx, "FPDF_NewXObjectFromPage", p.document.handle, src.handle, api.EncodeI32(int32(pageIndex)))
+	if err != nil {
+		return err
+	}
+
+	if xobject == 0 {
+		return fmt.Errorf("pdfium: could not create XObject from page %d", pageIndex)
+	}
+
+	// Form objects created from the XObject stay valid after it is closed.
+	object, err := i.call1(ctx, "FPDF_NewFormObjectFromXObject", xobject)
+	i.call(ctx, "FPDF_CloseXObject", xobject)
+	if err != nil {
+		return err
+	}
+
+	if object == 0 {
+		return fmt.Errorf("pdfium: could not create form object from page %d", pageIndex)
+	}
+
+	return p.insertObject(ctx, object, func() error {
+		return p.transformObject(ctx, object, Matrix{
+			A: scale,
+			D: scale,
+			E: cell.Left + (cellWidth-width*scale)/2,
+			F: cell.Bottom + (cellHeight-height*scale)/2,
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestBookletSheets(t *testing.T) {
+	tests := []struct {
+		pageCount int
+		want      [][2][2]int
+	}{
+		{pageCount: 0, want: [][2][2]int{}},
+		{pageCount: 1, want: [][2][2]int{
+			{{3, 0}, {1, 2}},
+		}},
+		{pageCount: 4, want: [][2][2]int{
+			{{3, 0}, {1, 2}},
+		}},
+		{pageCount: 5, want: [][2][2]int{
+			{{7, 0}, {1, 6}},
+			{{5, 2}, {3, 4}},
+		}},
+		{pageCount: 8, want: [][2][2]int{
+			{{7, 0}, {1, 6}},
+			{{5, 2}, {3, 4}},
+		}},
+		{pageCount: 12, want: [][2][2]int{
+			{{11, 0}, {1, 10}},
+			{{9, 2}, {3, 8}},
+			{{7, 4}, {5, 6}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.pageCount), func(t *testing.T) {
+			got := bookletSheets(test.pageCount)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("bookletSheets(%d) = %v, want %v", test.pageCount, got, test.want)
+			}
+		})
+	}
+}
+
+func TestBookletSheetsUsesEveryPageOnce(t *testing.T) {
+	for pageCount := 1; pageCount <= 40; pageCount++ {
+		padded := (pageCount + 3) / 4 * 4
+
+		seen := make([]bool, padded)
+		for _, sides := range bookletSheets(pageCount) {
+			for _, pages := range sides {
+				// Facing pages of a folded sheet always add up to the last page.
+				if pages[0]+pages[1] != padded-1 {
+					t.Errorf("bookletSheets(%d): pages %v do not face each other", pageCount, pages)
+				}
+
+				for _, pageIndex := range pages {
+					if seen[pageIndex] {
+						t.Errorf("bookletSheets(%d): page %d is used twice", pageCount, pageIndex)
+					}
+					seen[pageIndex] = true
+				}
+			}
+		}
+
+		for pageIndex, ok := range seen {
+			if !ok {
+				t.Errorf("bookletSheets(%d): page %d is missing", pageCount, pageIndex)
+			}
+		}
+	}
+}
+
+func TestPaperSizeOrientation(t *testing.T) {
+	landscape := PaperSize{Width: 841.89, Height: 595.28}
+
+	for _, paper := range []PaperSize{PaperA4, landscape} {
+		if got := paper.Landscape(); got != landscape {
+			t.Errorf("%v.Landscape() = %v, want %v", paper, got, landscape)
+		}
+		if got := paper.Portrait(); got != PaperA4 {
+			t.Errorf("%v.Portrait() = %v, want %v", paper, got, PaperA4)
+		}
+	}
+}
+
+// newNUpInstance returns an instance where FPDF_ImportNPagesToOne creates
+// document 9.
+func newNUpInstance(t *testing.T) *Instance {
+	t.Helper()
+
+	return newTestInstance(t, map[string]testFunction{
+		"FPDF_ImportNPagesToOne": returnI32([]api.ValueType{api.ValueTypeI32, api.ValueTypeF32, api.ValueTypeF32, api.ValueTypeI32, api.ValueTypeI32}, 9),
+	})
+}
+
+func TestImpose(t *testing.T) {
+	i := newNUpInstance(t)
+	doc := &Document{instance: i, handle: 1}
+
+	for _, imposition := range []Imposition{Imposition2Up, Imposition4Up} {
+		imposed, err := i.Impose(context.Background(), doc, imposition, PaperA4)
+		if err != nil {
+			t.Fatalf("Impose(%d) error: %v", imposition, err)
+		}
+
+		if imposed.handle != 9 {
+			t.Errorf("Impose(%d) = document %d, want 9", imposition, imposed.handle)
+		}
+	}
+
+	if _, err := i.Impose(context.Background(), doc, 0, PaperA4); err == nil {
+		t.Error("Impose(0) error = nil, want an error")
+	}
+}
+
+func TestNUpInvalid(t *testing.T) {
+	i := newNUpInstance(t)
+	doc := &Document{instance: i, handle: 1}
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		columns, rows int
+		paper         PaperSize
+	}{
+		{name: "no columns", columns: 0, rows: 1, paper: PaperA4},
+		{name: "negative rows", columns: 2, rows: -1, paper: PaperA4},
+		{name: "empty paper", columns: 2, rows: 1, paper: PaperSize{}},
+		{name: "negative paper", columns: 2, rows: 1, paper: PaperSize{Width: -1, Height: 100}},
+	}
+
+	for _, test := range tests {
+		if _, err := i.NUp(ctx, doc, test.columns, test.rows, test.paper); err == nil {
+			t.Errorf("NUp() with %s error = nil, want an error", test.name)
+		}
+	}
+
+	other := &Document{instance: newNUpInstance(t), handle: 1}
+	if _, err := i.NUp(ctx, other, 2, 1, PaperA4); !errors.Is(err, errOtherInstance) {
+		t.Errorf("NUp() of a document of another instance error = %v, want errOtherInstance", err)
+	}
+}
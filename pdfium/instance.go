@@ -0,0 +1,297 @@
+package pdfium
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Instance is a single PDFium module with its own linear memory. PDFium is not
+// thread-safe, so an Instance must not be used from multiple goroutines at the
+// same time.
+type Instance struct {
+	mod api.Module
+
+	malloc api.Function
+	free   api.Function
+}
+
+func newInstance(ctx context.Context, mod api.Module) (*Instance, error) {
+	inst := &Instance{
+		mod:    mod,
+		malloc: mod.ExportedFunction("malloc"),
+		free:   mod.ExportedFunction("free"),
+	}
+
+	if inst.malloc == nil || inst.free == nil {
+		mod.Close(ctx)
+		return nil, errors.New("pdfium: module does not export malloc and free")
+	}
+
+	if _, err := inst.call(ctx, "FPDF_InitLibrary"); err != nil {
+		mod.Close(ctx)
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// Module returns the underlying wazero module, for calling PDFium exports
+// that are not wrapped by this package.
+func (i *Instance) Module() api.Module {
+	return i.mod
+}
+
+// Close destroys the PDFium library and closes the module. Every document,
+// page and bitmap of the instance becomes invalid.
+func (i *Instance) Close(ctx context.Context) error {
+	if _, err := i.call(ctx, "FPDF_DestroyLibrary"); err != nil {
+		i.mod.Close(ctx)
+		return err
+	}
+
+	return i.mod.Close(ctx)
+}
+
+// call calls the exported PDFium function with the given name.
+func (i *Instance) call(ctx context.Context, name string, params ...uint64) ([]uint64, error) {
+	fn := i.mod.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("pdfium: function %s is not exported", name)
+	}
+
+	results, err := fn.Call(ctx, params...)
+	if err != nil {
+		return nil, &CallError{Name: name, Err: err}
+	}
+
+	return results, nil
+}
+
+// call1 calls the exported PDFium function with the given name and returns
+// its only result.
+func (i *Instance) call1(ctx context.Context, name string, params ...uint64) (uint64, error) {
+	results, err := i.call(ctx, name, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(results) != 1 {
+		return 0, fmt.Errorf("pdfium: %s returned %d results, expected 1", name, len(results))
+	}
+
+	return results[0], nil
+}
+
+// alloc allocates size bytes in linear memory. The returned pointer must be
+// released with release.
+func (i *Instance) alloc(ctx context.Context, size uint64) (uint64, error) {
+	results, err := i.malloc.Call(ctx, size)
+	if err != nil {
+		return 0, &CallError{Name: "malloc", Err: err}
+	}
+
+	if results[0] == 0 {
+		return 0, fmt.Errorf("pdfium: could not allocate %d bytes", size)
+	}
+
+	return results[0], nil
+}
+
+// release frees a pointer that was allocated by alloc.
+func (i *Instance) release(ctx context.Context, pointer uint64) error {
+	if pointer == 0 {
+		return nil
+	}
+
+	if _, err := i.free.Call(ctx, pointer); err != nil {
+		return &CallError{Name: "free", Err: err}
+	}
+
+	return nil
+}
+
+// allocBytes copies data into newly allocated linear memory.
+func (i *Instance) allocBytes(ctx context.Context, data []byte) (uint64, error) {
+	size := uint64(len(data))
+	if size == 0 {
+		// malloc(0) may return a null pointer, always allocate at least a byte.
+		size = 1
+	}
+
+	pointer, err := i.alloc(ctx, size)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := i.write(ctx, pointer, data); err != nil {
+		i.release(ctx, pointer)
+		return 0, err
+	}
+
+	return pointer, nil
+}
+
+// allocCString copies s into newly allocated linear memory as a NUL
+// terminated string.
+func (i *Instance) allocCString(ctx context.Context, s string) (uint64, error) {
+	return i.allocBytes(ctx, append([]byte(s), 0))
+}
+
+// allocFloat32s copies values into newly allocated linear memory, like an
+// FS_RECTF or an array of FS_POINTF.
+func (i *Instance) allocFloat32s(ctx context.Context, values []float32) (uint64, error) {
+	data := make([]byte, len(values)*4)
+	for n, value := range values {
+		binary.LittleEndian.PutUint32(data[n*4:], math.Float32bits(value))
+	}
+
+	return i.allocBytes(ctx, data)
+}
+
+// allocInt32s copies values into newly allocated linear memory, like an
+// array of page indexes.
+func (i *Instance) allocInt32s(ctx context.Context, values []int32) (uint64, error) {
+	data := make([]byte, len(values)*4)
+	for n, value := range values {
+		binary.LittleEndian.PutUint32(data[n*4:], uint32(value))
+	}
+
+	return i.allocBytes(ctx, data)
+}
+
+// write writes data to linear memory at pointer.
+func (i *Instance) write(ctx context.Context, pointer uint64, data []byte) error {
+	if !i.mod.Memory().Write(ctx, uint32(pointer), data) {
+		return fmt.Errorf("pdfium: Memory.Write(%d, %d) out of range of memory size %d", pointer, len(data), i.mod.Memory().Size(ctx))
+	}
+
+	return nil
+}
+
+// view returns a view of size bytes of linear memory at pointer. The view is
+// only valid until the memory grows, use read for a copy.
+func (i *Instance) view(ctx context.Context, pointer uint64, size uint64) ([]byte, error) {
+	data, ok := i.mod.Memory().Read(ctx, uint32(pointer), uint32(size))
+	if !ok {
+		return nil, fmt.Errorf("pdfium: Memory.Read(%d, %d) out of range of memory size %d", pointer, size, i.mod.Memory().Size(ctx))
+	}
+
+	return data, nil
+}
+
+// read returns a copy of size bytes of linear memory at pointer.
+func (i *Instance) read(ctx context.Context, pointer uint64, size uint64) ([]byte, error) {
+	data, err := i.view(ctx, pointer, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), data...), nil
+}
+
+// readFloat64s reads count float64 values at pointer.
+func (i *Instance) readFloat64s(ctx context.Context, pointer uint64, count int) ([]float64, error) {
+	values := make([]float64, count)
+	for n := range values {
+		value, ok := i.mod.Memory().ReadFloat64Le(ctx, uint32(pointer)+uint32(n*8))
+		if !ok {
+			return nil, fmt.Errorf("pdfium: Memory.ReadFloat64Le(%d) out of range of memory size %d", pointer+uint64(n*8), i.mod.Memory().Size(ctx))
+		}
+		values[n] = value
+	}
+
+	return values, nil
+}
+
+// readFloat32s reads count float32 values at pointer.
+func (i *Instance) readFloat32s(ctx context.Context, pointer uint64, count int) ([]float32, error) {
+	values := make([]float32, count)
+	for n := range values {
+		value, ok := i.mod.Memory().ReadFloat32Le(ctx, uint32(pointer)+uint32(n*4))
+		if !ok {
+			return nil, fmt.Errorf("pdfium: Memory.ReadFloat32Le(%d) out of range of memory size %d", pointer+uint64(n*4), i.mod.Memory().Size(ctx))
+		}
+		values[n] = value
+	}
+
+	return values, nil
+}
+
+// readInt32s reads count int32 values at pointer.
+func (i *Instance) readInt32s(ctx context.Context, pointer uint64, count int) ([]int32, error) {
+	values := make([]int32, count)
+	for n := range values {
+		value, ok := i.mod.Memory().ReadUint32Le(ctx, uint32(pointer)+uint32(n*4))
+		if !ok {
+			return nil, fmt.Errorf("pdfium: Memory.ReadUint32Le(%d) out of range of memory size %d", pointer+uint64(n*4), i.mod.Memory().Size(ctx))
+		}
+		values[n] = int32(value)
+	}
+
+	return values, nil
+}
+
+// callOutFloat64s allocates room for count float64 out parameters, calls the
+// function with the given name with the parameters params returns for a
+// pointer to them and reads them back. It fails with a *falseError when the
+// function returns false.
+func (i *Instance) callOutFloat64s(ctx context.Context, name string, count int, params func(pointer uint64) []uint64) ([]float64, error) {
+	pointer, err := i.alloc(ctx, uint64(count*8))
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, name, params(pointer)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if success == 0 {
+		return nil, &falseError{name: name}
+	}
+
+	return i.readFloat64s(ctx, pointer, count)
+}
+
+// callOutFloat32s is like callOutFloat64s for float32 out parameters, like an
+// FS_RECTF.
+func (i *Instance) callOutFloat32s(ctx context.Context, name string, count int, params func(pointer uint64) []uint64) ([]float32, error) {
+	pointer, err := i.alloc(ctx, uint64(count*4))
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, name, params(pointer)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if success == 0 {
+		return nil, &falseError{name: name}
+	}
+
+	return i.readFloat32s(ctx, pointer, count)
+}
+
+// falseError is returned by the callOut functions when the PDFium function
+// returns false, usually because the value is not available.
+type falseError struct {
+	name string
+}
+
+func (e *falseError) Error() string {
+	return fmt.Sprintf("pdfium: %s failed", e.name)
+}
+
+// isFalse returns whether err is a falseError.
+func isFalse(err error) bool {
+	var falseErr *falseError
+	return errors.As(err, &falseErr)
+}
@@ -0,0 +1,284 @@
+package pdfium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestInstanceMemory(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, nil)
+
+	pointer, err := i.allocBytes(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatalf("allocBytes() error: %v", err)
+	}
+
+	if pointer < testHeapStart {
+		t.Errorf("allocBytes() = %d, want a pointer into the heap", pointer)
+	}
+
+	data, err := i.read(ctx, pointer, 5)
+	if err != nil {
+		t.Fatalf("read() error: %v", err)
+	}
+
+	if string(data) != "hello" {
+		t.Errorf("read() = %q, want %q", data, "hello")
+	}
+
+	// read returns a copy, view does not.
+	if err := i.write(ctx, pointer, []byte("j")); err != nil {
+		t.Fatalf("write() error: %v", err)
+	}
+
+	view, err := i.view(ctx, pointer, 5)
+	if err != nil {
+		t.Fatalf("view() error: %v", err)
+	}
+
+	if string(data) != "hello" || string(view) != "jello" {
+		t.Errorf("after write, read() = %q and view() = %q, want %q and %q", data, view, "hello", "jello")
+	}
+
+	cString, err := i.allocCString(ctx, "abc")
+	if err != nil {
+		t.Fatalf("allocCString() error: %v", err)
+	}
+
+	if data, _ := i.read(ctx, cString, 4); !bytes.Equal(data, []byte("abc\x00")) {
+		t.Errorf("allocCString() wrote %q, want a NUL terminated string", data)
+	}
+
+	if empty, err := i.allocBytes(ctx, nil); err != nil || empty == 0 {
+		t.Errorf("allocBytes(nil) = %d, %v, want a pointer", empty, err)
+	}
+
+	if err := i.release(ctx, 0); err != nil {
+		t.Errorf("release(0) error: %v", err)
+	}
+}
+
+func TestInstanceMemoryErrors(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, nil)
+
+	if _, err := i.alloc(ctx, 0x20000); err == nil || !strings.Contains(err.Error(), "could not allocate") {
+		t.Errorf("alloc() of too much memory error = %v, want an allocation error", err)
+	}
+
+	memorySize := uint64(i.mod.Memory().Size(ctx))
+
+	if err := i.write(ctx, memorySize-1, []byte("ab")); err == nil {
+		t.Error("write() past the end of memory succeeded, want an error")
+	}
+
+	if _, err := i.view(ctx, memorySize-1, 2); err == nil {
+		t.Error("view() past the end of memory succeeded, want an error")
+	}
+
+	if _, err := i.read(ctx, memorySize, 1); err == nil {
+		t.Error("read() past the end of memory succeeded, want an error")
+	}
+}
+
+func TestInstanceCall(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"answer": returnI32(i32s(1), 42),
+		"trap":   trap(nil, nil),
+		"none":   {},
+	})
+
+	if result, err := i.call1(ctx, "answer", 0); err != nil || result != 42 {
+		t.Errorf("call1(answer) = %d, %v, want 42", result, err)
+	}
+
+	_, err := i.call1(ctx, "missing")
+	if err == nil || !strings.Contains(err.Error(), "missing is not exported") {
+		t.Errorf("call1(missing) error = %v, want a not exported error", err)
+	}
+
+	var callErr *CallError
+	if _, err := i.call(ctx, "trap"); !errors.As(err, &callErr) || callErr.Name != "trap" {
+		t.Errorf("call(trap) error = %v, want a *CallError for trap", err)
+	}
+
+	if _, err := i.call1(ctx, "none"); err == nil || !strings.Contains(err.Error(), "returned 0 results") {
+		t.Errorf("call1(none) error = %v, want a result count error", err)
+	}
+}
+
+func TestNewInstanceInitFails(t *testing.T) {
+	runtime := newTestRuntime(t, map[string]testFunction{
+		"FPDF_InitLibrary": trap(nil, nil),
+	})
+
+	_, err := runtime.NewInstance(context.Background())
+
+	var callErr *CallError
+	if !errors.As(err, &callErr) || callErr.Name != "FPDF_InitLibrary" {
+		t.Errorf("NewInstance() error = %v, want a *CallError for FPDF_InitLibrary", err)
+	}
+}
+
+func TestReadValues(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"store": {
+			params: i32s(1),
+			body:   join(storeFloat64s(0, 1.5, -2), storeInt32s(0, 0, -1), storeFloat32s(0, 0.25)),
+		},
+	})
+
+	pointer, err := i.alloc(ctx, 16)
+	if err != nil {
+		t.Fatalf("alloc() error: %v", err)
+	}
+
+	// The float32 and int32 values partly overwrite the first float64 value.
+	if _, err := i.call(ctx, "store", pointer); err != nil {
+		t.Fatalf("call(store) error: %v", err)
+	}
+
+	if float64s, err := i.readFloat64s(ctx, pointer+8, 1); err != nil || !reflect.DeepEqual(float64s, []float64{-2}) {
+		t.Errorf("readFloat64s() = %v, %v, want [-2]", float64s, err)
+	}
+
+	if float32s, err := i.readFloat32s(ctx, pointer, 1); err != nil || !reflect.DeepEqual(float32s, []float32{0.25}) {
+		t.Errorf("readFloat32s() = %v, %v, want [0.25]", float32s, err)
+	}
+
+	if int32s, err := i.readInt32s(ctx, pointer+4, 1); err != nil || !reflect.DeepEqual(int32s, []int32{-1}) {
+		t.Errorf("readInt32s() = %v, %v, want [-1]", int32s, err)
+	}
+
+	memorySize := uint64(i.mod.Memory().Size(ctx))
+
+	if _, err := i.readFloat64s(ctx, memorySize-8, 2); err == nil {
+		t.Error("readFloat64s() past the end of memory succeeded, want an error")
+	}
+
+	if _, err := i.readFloat32s(ctx, memorySize-4, 2); err == nil {
+		t.Error("readFloat32s() past the end of memory succeeded, want an error")
+	}
+
+	if _, err := i.readInt32s(ctx, memorySize-4, 2); err == nil {
+		t.Error("readInt32s() past the end of memory succeeded, want an error")
+	}
+}
+
+func TestCallOutFloat64s(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_Out": {
+			params:  []api.ValueType{api.ValueTypeI32, api.ValueTypeI32},
+			results: i32s(1),
+			body:    join(storeFloat64s(1, 1.5, -2.25), localGet(0)),
+		},
+	})
+
+	values, err := i.callOutFloat64s(ctx, "FPDF_Out", 2, func(pointer uint64) []uint64 {
+		return []uint64{1, pointer}
+	})
+	if err != nil {
+		t.Fatalf("callOutFloat64s() error: %v", err)
+	}
+
+	if want := []float64{1.5, -2.25}; !reflect.DeepEqual(values, want) {
+		t.Errorf("callOutFloat64s() = %v, want %v", values, want)
+	}
+
+	_, err = i.callOutFloat64s(ctx, "FPDF_Out", 2, func(pointer uint64) []uint64 {
+		return []uint64{0, pointer}
+	})
+	if !isFalse(err) {
+		t.Errorf("callOutFloat64s() of a false result error = %v, want a false error", err)
+	}
+
+	if err == nil || err.Error() != "pdfium: FPDF_Out failed" {
+		t.Errorf("callOutFloat64s() error = %v, want it to name FPDF_Out", err)
+	}
+
+	_, err = i.callOutFloat64s(ctx, "FPDF_Missing", 2, func(pointer uint64) []uint64 {
+		return []uint64{pointer}
+	})
+	if err == nil || isFalse(err) {
+		t.Errorf("callOutFloat64s() of a missing function error = %v, want another error than a false error", err)
+	}
+}
+
+func TestCallOutFloat32s(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDF_Out": {
+			params:  []api.ValueType{api.ValueTypeI32, api.ValueTypeI32},
+			results: i32s(1),
+			body:    join(storeFloat32s(1, 1, 2, 3, 4), localGet(0)),
+		},
+		"FPDF_Trap": trap(i32s(1), i32s(1)),
+	})
+
+	values, err := i.callOutFloat32s(ctx, "FPDF_Out", 4, func(pointer uint64) []uint64 {
+		return []uint64{1, pointer}
+	})
+	if err != nil {
+		t.Fatalf("callOutFloat32s() error: %v", err)
+	}
+
+	if want := []float32{1, 2, 3, 4}; !reflect.DeepEqual(values, want) {
+		t.Errorf("callOutFloat32s() = %v, want %v", values, want)
+	}
+
+	_, err = i.callOutFloat32s(ctx, "FPDF_Out", 4, func(pointer uint64) []uint64 {
+		return []uint64{0, pointer}
+	})
+	if !isFalse(err) {
+		t.Errorf("callOutFloat32s() of a false result error = %v, want a false error", err)
+	}
+
+	_, err = i.callOutFloat32s(ctx, "FPDF_Trap", 4, func(pointer uint64) []uint64 {
+		return []uint64{pointer}
+	})
+
+	var callErr *CallError
+	if !errors.As(err, &callErr) || isFalse(err) {
+		t.Errorf("callOutFloat32s() of a trap error = %v, want a *CallError", err)
+	}
+}
+
+func TestAllocFloat32s(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, nil)
+
+	values := []float32{1.5, -2, 0}
+	pointer, err := i.allocFloat32s(ctx, values)
+	if err != nil {
+		t.Fatalf("allocFloat32s() error: %v", err)
+	}
+
+	if got, err := i.readFloat32s(ctx, pointer, len(values)); err != nil || !reflect.DeepEqual(got, values) {
+		t.Errorf("readFloat32s() = %v, %v, want %v", got, err, values)
+	}
+}
+
+func TestAllocInt32s(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, nil)
+
+	values := []int32{3, -1, 0, 1 << 30}
+	pointer, err := i.allocInt32s(ctx, values)
+	if err != nil {
+		t.Fatalf("allocInt32s() error: %v", err)
+	}
+
+	if got, err := i.readInt32s(ctx, pointer, len(values)); err != nil || !reflect.DeepEqual(got, values) {
+		t.Errorf("readInt32s() = %v, %v, want %v", got, err, values)
+	}
+}
@@ -0,0 +1,270 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"image"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Link is a link annotation on a page.
+type Link struct {
+	// Rect is the area of the link in page coordinates.
+	Rect Rect
+
+	// ImageRect is the area of the link in the image rendered with the render
+	// options passed to Page.Links, empty when none were passed.
+	ImageRect image.Rectangle
+
+	// Destination is the target of the link within the document, from either
+	// its destination or its go to action. It is nil for external links.
+	Destination *Destination
+
+	// ActionType is the type of the action of the link.
+	// ActionTypeUnsupported when it has no action.
+	ActionType ActionType
+
+	// URI is the target of a URI action.
+	URI string
+}
+
+// WebLink is a URL found in the text of a page.
+type WebLink struct {
+	URL string
+
+	// Rects cover the text of the URL in page coordinates, one per line.
+	Rects []Rect
+
+	// ImageRects are Rects in the image rendered with the render options
+	// passed to Page.WebLinks, nil when none were passed.
+	ImageRects []image.Rectangle
+
+	// CharIndex and CharCount are the range of the URL in the text page.
+	CharIndex int
+	CharCount int
+}
+
+// Links returns the link annotations of the page. When render is not nil,
+// the areas of the links are also converted to the image RenderPage creates
+// with it.
+func (p *Page) Links(ctx context.Context, render *RenderOptions) ([]Link, error) {
+	d := p.document
+	i := d.instance
+
+	// An int for the start position followed by an FPDF_LINK.
+	pointer, err := i.alloc(ctx, 8)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, pointer)
+
+	if !i.mod.Memory().WriteUint32Le(ctx, uint32(pointer), 0) {
+		return nil, errors.New("pdfium: could not write link start position")
+	}
+
+	var links []Link
+	for {
+		found, err := i.call1(ctx, "FPDFLink_Enumerate", p.handle, pointer, pointer+4)
+		if err != nil {
+			return nil, err
+		}
+
+		if found == 0 {
+			break
+		}
+
+		handle, ok := i.mod.Memory().ReadUint32Le(ctx, uint32(pointer)+4)
+		if !ok {
+			return nil, errors.New("pdfium: could not read link")
+		}
+
+		link, err := p.link(ctx, uint64(handle), render)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, *link)
+	}
+
+	return links, nil
+}
+
+// link reads the FPDF_LINK handle.
+func (p *Page) link(ctx context.Context, handle uint64, render *RenderOptions) (*Link, error) {
+	d := p.document
+	i := d.instance
+
+	values, err := i.callOutFloat32s(ctx, "FPDFLink_GetAnnotRect", 4, func(pointer uint64) []uint64 {
+		return []uint64{handle, pointer}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link := &Link{
+		Rect: Rect{Left: float64(values[0]), Top: float64(values[1]), Right: float64(values[2]), Bottom: float64(values[3])},
+	}
+
+	if render != nil {
+		if link.ImageRect, err = i.ImageRect(ctx, p, *render, link.Rect); err != nil {
+			return nil, err
+		}
+	}
+
+	dest, err := i.call1(ctx, "FPDFLink_GetDest", d.handle, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if dest != 0 {
+		if link.Destination, err = d.destination(ctx, dest); err != nil {
+			return nil, err
+		}
+	}
+
+	action, err := i.call1(ctx, "FPDFLink_GetAction", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if action == 0 {
+		return link, nil
+	}
+
+	if link.ActionType, err = d.actionType(ctx, action); err != nil {
+		return nil, err
+	}
+
+	switch link.ActionType {
+	case ActionTypeGoTo:
+		if link.Destination == nil {
+			if link.Destination, err = d.actionDestination(ctx, action); err != nil {
+				return nil, err
+			}
+		}
+	case ActionTypeURI:
+		if link.URI, err = d.actionURI(ctx, action); err != nil {
+			return nil, err
+		}
+	}
+
+	return link, nil
+}
+
+// WebLinks detects the URLs in the text of the page. When render is not nil,
+// the areas of the URLs are also converted to the image RenderPage creates
+// with it.
+func (p *Page) WebLinks(ctx context.Context, render *RenderOptions) ([]WebLink, error) {
+	i := p.document.instance
+
+	textPage, err := p.LoadTextPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer textPage.Close(ctx)
+
+	pageLink, err := i.call1(ctx, "FPDFLink_LoadWebLinks", textPage.handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if pageLink == 0 {
+		return nil, errors.New("pdfium: could not load web links")
+	}
+	defer i.call(ctx, "FPDFLink_CloseWebLinks", pageLink)
+
+	count, err := i.call1(ctx, "FPDFLink_CountWebLinks", pageLink)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []WebLink
+	for index := 0; index < int(int32(count)); index++ {
+		link, err := p.webLink(ctx, pageLink, index, render)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, *link)
+	}
+
+	return links, nil
+}
+
+// webLink reads the web link at index of pageLink.
+func (p *Page) webLink(ctx context.Context, pageLink uint64, index int, render *RenderOptions) (*WebLink, error) {
+	i := p.document.instance
+	linkIndex := api.EncodeI32(int32(index))
+
+	// FPDFLink_GetURL counts in UTF-16 code units instead of bytes, including
+	// the terminating NUL.
+	length, err := i.call1(ctx, "FPDFLink_GetURL", pageLink, linkIndex, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &WebLink{}
+
+	if int32(length) > 1 {
+		bufferPointer, err := i.alloc(ctx, length*2)
+		if err != nil {
+			return nil, err
+		}
+		defer i.release(ctx, bufferPointer)
+
+		if _, err := i.call1(ctx, "FPDFLink_GetURL", pageLink, linkIndex, bufferPointer, length); err != nil {
+			return nil, err
+		}
+
+		if link.URL, err = i.readUTF16(ctx, bufferPointer, length*2); err != nil {
+			return nil, err
+		}
+	}
+
+	rectCount, err := i.call1(ctx, "FPDFLink_CountRects", pageLink, linkIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	for rectIndex := 0; rectIndex < int(int32(rectCount)); rectIndex++ {
+		values, err := i.callOutFloat64s(ctx, "FPDFLink_GetRect", 4, func(pointer uint64) []uint64 {
+			return []uint64{pageLink, linkIndex, api.EncodeI32(int32(rectIndex)), pointer, pointer + 8, pointer + 16, pointer + 24}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rect := Rect{Left: values[0], Top: values[1], Right: values[2], Bottom: values[3]}
+		link.Rects = append(link.Rects, rect)
+
+		if render != nil {
+			imageRect, err := i.ImageRect(ctx, p, *render, rect)
+			if err != nil {
+				return nil, err
+			}
+			link.ImageRects = append(link.ImageRects, imageRect)
+		}
+	}
+
+	rangePointer, err := i.alloc(ctx, 8)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, rangePointer)
+
+	success, err := i.call1(ctx, "FPDFLink_GetTextRange", pageLink, linkIndex, rangePointer, rangePointer+4)
+	if err != nil {
+		return nil, err
+	}
+
+	if success != 0 {
+		textRange, err := i.readInt32s(ctx, rangePointer, 2)
+		if err != nil {
+			return nil, err
+		}
+		link.CharIndex, link.CharCount = int(textRange[0]), int(textRange[1])
+	}
+
+	return link, nil
+}
@@ -0,0 +1,62 @@
+package pdfium
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+func TestLinks(t *testing.T) {
+	// Enumerates two links, with handles 1 and 2.
+	enumerate := testFunction{
+		params:  i32s(3),
+		results: i32s(1),
+		body: join(
+			localGet(1), memarg(opI32Load, 2, 0), i32Const(2), []byte{opI32LtU, opIf, api.ValueTypeI32},
+			localGet(2), localGet(1), memarg(opI32Load, 2, 0), i32Const(1), []byte{opI32Add}, memarg(opI32Store, 2, 0),
+			localGet(1), localGet(1), memarg(opI32Load, 2, 0), i32Const(1), []byte{opI32Add}, memarg(opI32Store, 2, 0),
+			i32Const(1),
+			[]byte{opElse}, i32Const(0), []byte{opEnd},
+		),
+	}
+
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFLink_Enumerate": enumerate,
+		"FPDFLink_GetAnnotRect": {
+			params:  i32s(2),
+			results: i32s(1),
+			body:    join(storeFloat32s(1, 10, 20, 30, 5), i32Const(1)),
+		},
+		"FPDFLink_GetDest":   returnI32(i32s(2), 0),
+		"FPDFLink_GetAction": returnI32(i32s(1), 0),
+	})
+	page := &Page{document: &Document{instance: i, handle: 1}, handle: 1}
+
+	links, err := page.Links(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Links() error: %v", err)
+	}
+
+	link := Link{Rect: Rect{Left: 10, Top: 20, Right: 30, Bottom: 5}}
+	if want := []Link{link, link}; !reflect.DeepEqual(links, want) {
+		t.Errorf("Links() = %+v, want %+v", links, want)
+	}
+}
+
+func TestLinksNoRect(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFLink_Enumerate": {
+			params:  i32s(3),
+			results: i32s(1),
+			body:    join(storeInt32s(2, 1), i32Const(1)),
+		},
+		"FPDFLink_GetAnnotRect": returnI32(i32s(2), 0),
+	})
+	page := &Page{document: &Document{instance: i, handle: 1}, handle: 1}
+
+	if _, err := page.Links(context.Background(), nil); err == nil {
+		t.Error("Links() error = nil, want an error")
+	}
+}
@@ -0,0 +1,170 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errOtherInstance is returned when documents of different instances are
+// combined, PDFium can only copy pages within one module.
+var errOtherInstance = errors.New("pdfium: document belongs to another instance")
+
+// Merge creates a document with the pages of docs, in order. The viewer
+// preferences are copied from the first document. All documents must have
+// been loaded by this instance.
+func (i *Instance) Merge(ctx context.Context, docs ...*Document) (*Document, error) {
+	if len(docs) == 0 {
+		return nil, errors.New("pdfium: no documents to merge")
+	}
+
+	return i.newDocumentFrom(ctx, docs[0], func(merged *Document) error {
+		for _, doc := range docs {
+			pageCount, err := merged.PageCount(ctx)
+			if err != nil {
+				return err
+			}
+
+			if err := merged.importPages(ctx, doc, nil, pageCount); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Split creates a document per page range of doc. A page range is a string
+// like "1-3,5", with page numbers starting at 1.
+func (i *Instance) Split(ctx context.Context, doc *Document, ranges []string) ([]*Document, error) {
+	docs := make([]*Document, 0, len(ranges))
+	for _, pageRange := range ranges {
+		part, err := i.newDocumentFrom(ctx, doc, func(part *Document) error {
+			return part.importPageRange(ctx, doc, pageRange, 0)
+		})
+		if err != nil {
+			for _, part := range docs {
+				part.Close(ctx)
+			}
+			return nil, err
+		}
+
+		docs = append(docs, part)
+	}
+
+	return docs, nil
+}
+
+// Reorder creates a document with the pages of doc in the given order, as
+// page indexes starting at 0. Pages can be left out or repeated.
+func (i *Instance) Reorder(ctx context.Context, doc *Document, order []int) (*Document, error) {
+	if len(order) == 0 {
+		return nil, errors.New("pdfium: no pages to reorder")
+	}
+
+	pageCount, err := doc.PageCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range order {
+		if index < 0 || index >= pageCount {
+			return nil, fmt.Errorf("%w: index %d", ErrPage, index)
+		}
+	}
+
+	return i.newDocumentFrom(ctx, doc, func(reordered *Document) error {
+		return reordered.importPages(ctx, doc, order, 0)
+	})
+}
+
+// newDocumentFrom creates a document with the viewer preferences of src and
+// calls fn to fill it. The document is closed when fn fails.
+func (i *Instance) newDocumentFrom(ctx context.Context, src *Document, fn func(doc *Document) error) (*Document, error) {
+	if src.instance != i {
+		return nil, errOtherInstance
+	}
+
+	doc, err := i.NewDocument(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	success, err := i.call1(ctx, "FPDF_CopyViewerPreferences", doc.handle, src.handle)
+	if err == nil && success == 0 {
+		err = errors.New("pdfium: could not copy viewer preferences")
+	}
+
+	if err == nil {
+		err = fn(doc)
+	}
+
+	if err != nil {
+		doc.Close(ctx)
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// importPages copies the pages at indexes of src into the document at
+// insertIndex, all pages when indexes is nil.
+func (d *Document) importPages(ctx context.Context, src *Document, indexes []int, insertIndex int) error {
+	i := d.instance
+
+	if src.instance != i {
+		return errOtherInstance
+	}
+
+	var indexesPointer uint64
+	if indexes != nil {
+		values := make([]int32, len(indexes))
+		for n, index := range indexes {
+			values[n] = int32(index)
+		}
+
+		var err error
+		if indexesPointer, err = i.allocInt32s(ctx, values); err != nil {
+			return err
+		}
+		defer i.release(ctx, indexesPointer)
+	}
+
+	success, err := i.call1(ctx, "FPDF_ImportPagesByIndex", d.handle, src.handle, indexesPointer, uint64(len(indexes)), uint64(uint32(insertIndex)))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not import pages")
+	}
+
+	return nil
+}
+
+// importPageRange copies the pages in pageRange of src, like "1-3,5", into the
+// document at insertIndex.
+func (d *Document) importPageRange(ctx context.Context, src *Document, pageRange string, insertIndex int) error {
+	i := d.instance
+
+	if src.instance != i {
+		return errOtherInstance
+	}
+
+	rangePointer, err := i.allocCString(ctx, pageRange)
+	if err != nil {
+		return err
+	}
+	defer i.release(ctx, rangePointer)
+
+	success, err := i.call1(ctx, "FPDF_ImportPages", d.handle, src.handle, rangePointer, uint64(uint32(insertIndex)))
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not import page range %q", pageRange)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// importedPages is where the test FPDF_ImportPagesByIndex stores the number
+// of page indexes it was given.
+const importedPages = 0x10000
+
+// newMergeInstance returns an instance whose documents have two pages and
+// where new documents get handle 9.
+func newMergeInstance(t *testing.T) *Instance {
+	t.Helper()
+
+	return newTestInstance(t, map[string]testFunction{
+		"FPDF_CreateNewDocument":     returnI32(nil, 9),
+		"FPDF_CloseDocument":         {params: i32s(1)},
+		"FPDF_CopyViewerPreferences": returnI32(i32s(2), 1),
+		"FPDF_GetPageCount":          returnI32(i32s(1), 2),
+		"FPDF_ImportPagesByIndex": {
+			params:  i32s(5),
+			results: i32s(1),
+			body:    join(i32Const(importedPages), localGet(3), memarg(opI32Store, 2, 0), i32Const(1)),
+		},
+	})
+}
+
+func TestMerge(t *testing.T) {
+	i := newMergeInstance(t)
+	ctx := context.Background()
+
+	merged, err := i.Merge(ctx, &Document{instance: i, handle: 1}, &Document{instance: i, handle: 2})
+	if err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+
+	if merged.handle != 9 {
+		t.Errorf("Merge() = document %d, want 9", merged.handle)
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	i := newMergeInstance(t)
+	other := newMergeInstance(t)
+	ctx := context.Background()
+
+	if _, err := i.Merge(ctx); err == nil {
+		t.Error("Merge() without documents error = nil, want an error")
+	}
+
+	if _, err := i.Merge(ctx, &Document{instance: other, handle: 1}); !errors.Is(err, errOtherInstance) {
+		t.Errorf("Merge() of a document of another instance error = %v, want errOtherInstance", err)
+	}
+
+	if _, err := i.Merge(ctx, &Document{instance: i, handle: 1}, &Document{instance: other, handle: 1}); !errors.Is(err, errOtherInstance) {
+		t.Errorf("Merge() of a later document of another instance error = %v, want errOtherInstance", err)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	i := newMergeInstance(t)
+	ctx := context.Background()
+
+	if _, err := i.Reorder(ctx, &Document{instance: i, handle: 1}, []int{1, 0, 1}); err != nil {
+		t.Fatalf("Reorder() error: %v", err)
+	}
+
+	if count, _ := i.mod.Memory().ReadUint32Le(ctx, importedPages); count != 3 {
+		t.Errorf("Reorder() imported %d pages, want 3", count)
+	}
+}
+
+func TestReorderInvalid(t *testing.T) {
+	i := newMergeInstance(t)
+	doc := &Document{instance: i, handle: 1}
+	ctx := context.Background()
+
+	if _, err := i.Reorder(ctx, doc, nil); err == nil {
+		t.Error("Reorder() without pages error = nil, want an error")
+	}
+
+	for _, index := range []int{-1, 2} {
+		if _, err := i.Reorder(ctx, doc, []int{0, index}); !errors.Is(err, ErrPage) {
+			t.Errorf("Reorder() with index %d error = %v, want ErrPage", index, err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package pdfium
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Permissions are the user access permissions of a document, see table 22 of
+// the PDF 1.7 specification.
+type Permissions uint32
+
+const (
+	PermissionPrint            Permissions = 1 << 2
+	PermissionModify           Permissions = 1 << 3
+	PermissionCopy             Permissions = 1 << 4
+	PermissionAnnotate         Permissions = 1 << 5
+	PermissionFillForms        Permissions = 1 << 8
+	PermissionExtract          Permissions = 1 << 9
+	PermissionAssemble         Permissions = 1 << 10
+	PermissionPrintHighQuality Permissions = 1 << 11
+)
+
+// Has returns whether every permission in permission is granted.
+func (p Permissions) Has(permission Permissions) bool {
+	return p&permission == permission
+}
+
+// DocumentInfo is the metadata of a document.
+type DocumentInfo struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords string
+	Creator  string
+	Producer string
+
+	// CreationDate and ModDate are zero when the document does not have them
+	// or they can't be parsed.
+	CreationDate time.Time
+	ModDate      time.Time
+
+	// FileVersion is the PDF version times ten, so 17 for PDF 1.7. It is 0
+	// for documents that were not loaded from a file.
+	FileVersion int
+
+	Permissions Permissions
+
+	// PermanentID and ChangingID are the two parts of the file identifier.
+	PermanentID []byte
+	ChangingID  []byte
+
+	PageCount int
+}
+
+// Info returns the metadata of the document.
+func (d *Document) Info(ctx context.Context) (*DocumentInfo, error) {
+	info := &DocumentInfo{}
+
+	texts := []struct {
+		tag   string
+		value *string
+	}{
+		{"Title", &info.Title},
+		{"Author", &info.Author},
+		{"Subject", &info.Subject},
+		{"Keywords", &info.Keywords},
+		{"Creator", &info.Creator},
+		{"Producer", &info.Producer},
+	}
+
+	for _, s := range texts {
+		value, err := d.GetMetaText(ctx, s.tag)
+		if err != nil {
+			return nil, err
+		}
+		*s.value = value
+	}
+
+	dates := []struct {
+		tag   string
+		value *time.Time
+	}{
+		{"CreationDate", &info.CreationDate},
+		{"ModDate", &info.ModDate},
+	}
+
+	for _, date := range dates {
+		value, err := d.GetMetaText(ctx, date.tag)
+		if err != nil {
+			return nil, err
+		}
+
+		// A broken date is common and not worth failing over.
+		if parsed, err := ParseDate(value); err == nil {
+			*date.value = parsed
+		}
+	}
+
+	var err error
+	if info.FileVersion, err = d.FileVersion(ctx); err != nil {
+		return nil, err
+	}
+
+	if info.Permissions, err = d.Permissions(ctx); err != nil {
+		return nil, err
+	}
+
+	if info.PermanentID, err = d.fileIdentifier(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	if info.ChangingID, err = d.fileIdentifier(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	if info.PageCount, err = d.PageCount(ctx); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// GetMetaText returns the value of tag in the document information
+// dictionary, empty when it is not set.
+func (d *Document) GetMetaText(ctx context.Context, tag string) (string, error) {
+	i := d.instance
+
+	tagPointer, err := i.allocCString(ctx, tag)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, tagPointer)
+
+	return i.callUTF16(ctx, "FPDF_GetMetaText", d.handle, tagPointer)
+}
+
+// FileVersion returns the PDF version of the document times ten, or 0 when
+// the document was not loaded from a file.
+func (d *Document) FileVersion(ctx context.Context) (int, error) {
+	i := d.instance
+
+	pointer, err := i.alloc(ctx, 4)
+	if err != nil {
+		return 0, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, "FPDF_GetFileVersion", d.handle, pointer)
+	if err != nil {
+		return 0, err
+	}
+
+	if success == 0 {
+		return 0, nil
+	}
+
+	version, err := i.readInt32s(ctx, pointer, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(version[0]), nil
+}
+
+// Permissions returns the user access permissions of the document.
+func (d *Document) Permissions(ctx context.Context) (Permissions, error) {
+	permissions, err := d.instance.call1(ctx, "FPDF_GetDocPermissions", d.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return Permissions(uint32(permissions)), nil
+}
+
+// fileIdentifier returns the permanent (0) or changing (1) part of the file
+// identifier.
+func (d *Document) fileIdentifier(ctx context.Context, idType uint64) ([]byte, error) {
+	i := d.instance
+
+	// The length includes a terminating NUL.
+	length, err := i.call1(ctx, "FPDF_GetFileIdentifier", d.handle, idType, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint32(length) <= 1 {
+		return nil, nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, length)
+	if err != nil {
+		return nil, err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, "FPDF_GetFileIdentifier", d.handle, idType, bufferPointer, length); err != nil {
+		return nil, err
+	}
+
+	return i.read(ctx, bufferPointer, length-1)
+}
+
+var dateRegexp = regexp.MustCompile(`^(?:D:)?(\d{4})(\d{2})?(\d{2})?(\d{2})?(\d{2})?(\d{2})?(?:([Zz+-])(?:(\d{2})'?(?:(\d{2})'?)?)?)?$`)
+
+// ParseDate parses a PDF date string, like D:20221201153000+01'00'. Every
+// part after the year is optional, a missing time zone means UTC.
+func ParseDate(date string) (time.Time, error) {
+	parts := dateRegexp.FindStringSubmatch(date)
+	if parts == nil {
+		return time.Time{}, fmt.Errorf("pdfium: invalid date %q", date)
+	}
+
+	number := func(part string, fallback int) int {
+		if part == "" {
+			return fallback
+		}
+		value, _ := strconv.Atoi(part)
+		return value
+	}
+
+	month, day := number(parts[2], 1), number(parts[3], 1)
+	hour, minute, second := number(parts[4], 0), number(parts[5], 0), number(parts[6], 0)
+	offsetHours, offsetMinutes := number(parts[8], 0), number(parts[9], 0)
+
+	// The parts are digits only, so they can't be negative.
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 59 || offsetHours > 23 || offsetMinutes > 59 {
+		return time.Time{}, fmt.Errorf("pdfium: invalid date %q", date)
+	}
+
+	location := time.UTC
+	if parts[7] == "+" || parts[7] == "-" {
+		offset := offsetHours*3600 + offsetMinutes*60
+		if parts[7] == "-" {
+			offset = -offset
+		}
+		location = time.FixedZone("", offset)
+	}
+
+	return time.Date(
+		number(parts[1], 0),
+		time.Month(month),
+		day,
+		hour,
+		minute,
+		second,
+		0,
+		location,
+	), nil
+}
+
+// FormatDate formats t as a PDF date string, like D:20221201153000+01'00'.
+func FormatDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	return fmt.Sprintf("D:%s%c%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
@@ -0,0 +1,119 @@
+package pdfium
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		date    string
+		want    time.Time
+		wantErr bool
+	}{
+		{date: "D:20221201153045+01'00'", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.FixedZone("", 3600))},
+		{date: "D:20221201153045-05'30'", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.FixedZone("", -(5*3600+30*60)))},
+		{date: "D:20221201153045+01'00", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.FixedZone("", 3600))},
+		{date: "D:20221201153045+01", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.FixedZone("", 3600))},
+		{date: "D:20221201153045Z", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.UTC)},
+		{date: "D:20221201153045Z00'00'", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.UTC)},
+		{date: "D:20221201153045", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.UTC)},
+		{date: "20221201153045", want: time.Date(2022, 12, 1, 15, 30, 45, 0, time.UTC)},
+		{date: "D:202212011530", want: time.Date(2022, 12, 1, 15, 30, 0, 0, time.UTC)},
+		{date: "D:20221201", want: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{date: "D:202212", want: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{date: "D:2022", want: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{date: "D:20221231235959+23'59'", want: time.Date(2022, 12, 31, 23, 59, 59, 0, time.FixedZone("", 23*3600+59*60))},
+		{date: "", wantErr: true},
+		{date: "D:", wantErr: true},
+		{date: "D:22", wantErr: true},
+		{date: "December 1, 2022", wantErr: true},
+		{date: "D:20221201153045+01'00'junk", wantErr: true},
+		{date: "D:202200", wantErr: true},
+		{date: "D:202213", wantErr: true},
+		{date: "D:20221200", wantErr: true},
+		{date: "D:20221232", wantErr: true},
+		{date: "D:2022120124", wantErr: true},
+		{date: "D:202212011560", wantErr: true},
+		{date: "D:20221201153060", wantErr: true},
+		{date: "D:20221201153045+24'00'", wantErr: true},
+		{date: "D:20221201153045-01'60'", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.date, func(t *testing.T) {
+			got, err := ParseDate(test.date)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseDate(%q) = %v, want an error", test.date, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error: %v", test.date, err)
+			}
+
+			if !got.Equal(test.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", test.date, got, test.want)
+			}
+
+			_, gotOffset := got.Zone()
+			_, wantOffset := test.want.Zone()
+			if gotOffset != wantOffset {
+				t.Errorf("ParseDate(%q) offset = %d, want %d", test.date, gotOffset, wantOffset)
+			}
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	tests := []struct {
+		time time.Time
+		want string
+	}{
+		{time: time.Date(2022, 12, 1, 15, 30, 45, 0, time.UTC), want: "D:20221201153045+00'00'"},
+		{time: time.Date(2022, 12, 1, 15, 30, 45, 0, time.FixedZone("", 3600)), want: "D:20221201153045+01'00'"},
+		{time: time.Date(2022, 12, 1, 15, 30, 45, 0, time.FixedZone("", -(5*3600+30*60))), want: "D:20221201153045-05'30'"},
+		{time: time.Date(2022, 1, 2, 3, 4, 5, 0, time.FixedZone("", 5*3600+45*60)), want: "D:20220102030405+05'45'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			got := FormatDate(test.time)
+			if got != test.want {
+				t.Errorf("FormatDate(%v) = %q, want %q", test.time, got, test.want)
+			}
+
+			parsed, err := ParseDate(got)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error: %v", got, err)
+			}
+
+			if !parsed.Equal(test.time) {
+				t.Errorf("ParseDate(FormatDate(%v)) = %v", test.time, parsed)
+			}
+		})
+	}
+}
+
+func TestPermissionsHas(t *testing.T) {
+	permissions := PermissionPrint | PermissionCopy | PermissionFillForms
+
+	tests := []struct {
+		permission Permissions
+		want       bool
+	}{
+		{permission: PermissionPrint, want: true},
+		{permission: PermissionCopy | PermissionFillForms, want: true},
+		{permission: PermissionModify, want: false},
+		{permission: PermissionPrint | PermissionModify, want: false},
+		{permission: 0, want: true},
+	}
+
+	for _, test := range tests {
+		if got := permissions.Has(test.permission); got != test.want {
+			t.Errorf("Permissions(%#x).Has(%#x) = %v, want %v", permissions, test.permission, got, test.want)
+		}
+	}
+}
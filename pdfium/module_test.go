@@ -0,0 +1,263 @@
+package pdfium
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// The opcodes used by the bodies of test functions.
+const (
+	opUnreachable = 0x00
+	opIf          = 0x04
+	opElse        = 0x05
+	opEnd         = 0x0b
+	opLocalGet    = 0x20
+	opGlobalGet   = 0x23
+	opGlobalSet   = 0x24
+	opI32Load     = 0x28
+	opI32Store    = 0x36
+	opF32Store    = 0x38
+	opF64Store    = 0x39
+	opI32Store16  = 0x3b
+	opI32Const    = 0x41
+	opI32LtU      = 0x49
+	opI32GtU      = 0x4b
+	opI32Add      = 0x6a
+	opI32And      = 0x71
+	opI32Shl      = 0x74
+)
+
+// testHeapStart is where malloc of a test module starts allocating.
+const testHeapStart = 1024
+
+// testFunction is a function of a test module. The body is wasm bytecode,
+// without the final end.
+type testFunction struct {
+	params  []api.ValueType
+	results []api.ValueType
+	body    []byte
+}
+
+// join concatenates pieces of bytecode.
+func join(parts ...[]byte) []byte {
+	var b []byte
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return b
+}
+
+func appendULEB(b []byte, v uint64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func appendSLEB(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+// i32Const pushes v.
+func i32Const(v int32) []byte {
+	return appendSLEB([]byte{opI32Const}, int64(v))
+}
+
+// localGet pushes parameter n.
+func localGet(n int) []byte {
+	return appendULEB([]byte{opLocalGet}, uint64(n))
+}
+
+// memarg is the alignment and offset of a load or store.
+func memarg(op byte, align, offset uint64) []byte {
+	return appendULEB(appendULEB([]byte{op}, align), offset)
+}
+
+// storeFloat32s stores values at the pointer in parameter n.
+func storeFloat32s(n int, values ...float32) []byte {
+	var b []byte
+	for offset, value := range values {
+		b = append(b, localGet(n)...)
+		b = append(b, 0x43)
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(value))
+		b = append(b, memarg(opF32Store, 2, uint64(offset*4))...)
+	}
+	return b
+}
+
+// storeFloat64s stores values at the pointer in parameter n.
+func storeFloat64s(n int, values ...float64) []byte {
+	var b []byte
+	for offset, value := range values {
+		b = append(b, localGet(n)...)
+		b = append(b, 0x44)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(value))
+		b = append(b, memarg(opF64Store, 3, uint64(offset*8))...)
+	}
+	return b
+}
+
+// storeInt32s stores values at the pointer in parameter n.
+func storeInt32s(n int, values ...int32) []byte {
+	var b []byte
+	for offset, value := range values {
+		b = append(b, localGet(n)...)
+		b = append(b, i32Const(value)...)
+		b = append(b, memarg(opI32Store, 2, uint64(offset*4))...)
+	}
+	return b
+}
+
+// i32s returns count i32 value types.
+func i32s(count int) []api.ValueType {
+	types := make([]api.ValueType, count)
+	for n := range types {
+		types[n] = api.ValueTypeI32
+	}
+	return types
+}
+
+// returnI32 is a function with params that returns v.
+func returnI32(params []api.ValueType, v int32) testFunction {
+	return testFunction{
+		params:  params,
+		results: i32s(1),
+		body:    i32Const(v),
+	}
+}
+
+// trap is a function with params and results that traps.
+func trap(params, results []api.ValueType) testFunction {
+	return testFunction{
+		params:  params,
+		results: results,
+		body:    []byte{opUnreachable},
+	}
+}
+
+// testModule builds a wasm module that exports its memory and functions. Every
+// module gets a bump allocator as malloc, that fails for more than 64 KiB, and
+// a free that does nothing, unless functions replaces them.
+func testModule(functions map[string]testFunction) []byte {
+	all := map[string]testFunction{
+		"malloc": {
+			params:  i32s(1),
+			results: i32s(1),
+			body: join(
+				localGet(0), i32Const(0x10000), []byte{opI32GtU, opIf, api.ValueTypeI32},
+				i32Const(0),
+				[]byte{opElse, opGlobalGet, 0, opGlobalGet, 0}, localGet(0), []byte{opI32Add},
+				i32Const(7), []byte{opI32Add}, i32Const(-8), []byte{opI32And, opGlobalSet, 0, opEnd},
+			),
+		},
+		"free": {params: i32s(1)},
+	}
+	for name, function := range functions {
+		all[name] = function
+	}
+
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var types, funcs, exports, code []byte
+	types = appendULEB(types, uint64(len(names)))
+	funcs = appendULEB(funcs, uint64(len(names)))
+	exports = appendULEB(exports, uint64(len(names)+1))
+	exports = append(appendULEB(exports, 6), "memory"...)
+	exports = append(exports, 0x02, 0x00)
+	code = appendULEB(code, uint64(len(names)))
+
+	for index, name := range names {
+		function := all[name]
+
+		types = append(types, 0x60)
+		types = append(appendULEB(types, uint64(len(function.params))), function.params...)
+		types = append(appendULEB(types, uint64(len(function.results))), function.results...)
+
+		funcs = appendULEB(funcs, uint64(index))
+
+		exports = append(appendULEB(exports, uint64(len(name))), name...)
+		exports = appendULEB(append(exports, 0x00), uint64(index))
+
+		// No locals besides the parameters.
+		body := append(append([]byte{0x00}, function.body...), opEnd)
+		code = append(appendULEB(code, uint64(len(body))), body...)
+	}
+
+	// Two pages of memory and the mutable heap pointer of malloc.
+	memory := []byte{0x01, 0x00, 0x02}
+	globals := join([]byte{0x01, api.ValueTypeI32, 0x01}, i32Const(testHeapStart), []byte{opEnd})
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	for _, section := range []struct {
+		id      byte
+		content []byte
+	}{
+		{1, types},
+		{3, funcs},
+		{5, memory},
+		{6, globals},
+		{7, exports},
+		{10, code},
+	} {
+		module = append(module, section.id)
+		module = append(appendULEB(module, uint64(len(section.content))), section.content...)
+	}
+
+	return module
+}
+
+// newTestRuntime compiles a module built by testModule. FPDF_InitLibrary
+// and FPDF_DestroyLibrary are added when functions does not have them.
+func newTestRuntime(t *testing.T, functions map[string]testFunction) *Runtime {
+	t.Helper()
+
+	all := map[string]testFunction{
+		"FPDF_InitLibrary":    {},
+		"FPDF_DestroyLibrary": {},
+	}
+	for name, function := range functions {
+		all[name] = function
+	}
+
+	ctx := context.Background()
+	runtime, err := NewRuntime(ctx, testModule(all), Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime() error: %v", err)
+	}
+	t.Cleanup(func() { runtime.Close(ctx) })
+
+	return runtime
+}
+
+// newTestInstance creates an instance of a module built by testModule, see
+// newTestRuntime.
+func newTestInstance(t *testing.T, functions map[string]testFunction) *Instance {
+	t.Helper()
+
+	instance, err := newTestRuntime(t, functions).NewInstance(context.Background())
+	if err != nil {
+		t.Fatalf("NewInstance() error: %v", err)
+	}
+
+	return instance
+}
@@ -0,0 +1,135 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Page is a page of a Document.
+type Page struct {
+	document *Document
+	handle   uint64
+	index    int
+}
+
+// LoadPage loads the page at index, starting at 0.
+func (d *Document) LoadPage(ctx context.Context, index int) (*Page, error) {
+	handle, err := d.instance.call1(ctx, "FPDF_LoadPage", d.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, fmt.Errorf("%w: index %d", ErrPage, index)
+	}
+
+	if d.form != nil {
+		if _, err := d.instance.call(ctx, "FORM_OnAfterLoadPage", handle, d.form.handle); err != nil {
+			d.instance.call(ctx, "FPDF_ClosePage", handle)
+			return nil, err
+		}
+	}
+
+	return &Page{
+		document: d,
+		handle:   handle,
+		index:    index,
+	}, nil
+}
+
+// Index returns the index of the page in its document when it was loaded.
+// It is not updated when NewPage inserts or DeletePage removes a page before
+// it.
+func (p *Page) Index() int {
+	return p.index
+}
+
+// Document returns the document the page was loaded from.
+func (p *Page) Document() *Document {
+	return p.document
+}
+
+// Width returns the width of the page in points.
+func (p *Page) Width(ctx context.Context) (float32, error) {
+	width, err := p.document.instance.call1(ctx, "FPDF_GetPageWidthF", p.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return api.DecodeF32(width), nil
+}
+
+// Height returns the height of the page in points.
+func (p *Page) Height(ctx context.Context) (float32, error) {
+	height, err := p.document.instance.call1(ctx, "FPDF_GetPageHeightF", p.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return api.DecodeF32(height), nil
+}
+
+// RenderPageBitmap renders the page into bitmap. The page is scaled to
+// sizeX x sizeY pixels and placed at startX, startY. Rotate is the number of
+// clockwise quarter turns and flags is a combination of the FPDF_* render
+// flags.
+func (p *Page) RenderPageBitmap(ctx context.Context, bitmap *Bitmap, startX, startY, sizeX, sizeY, rotate, flags int) error {
+	_, err := p.document.instance.call(ctx, "FPDF_RenderPageBitmap",
+		bitmap.handle,
+		p.handle,
+		api.EncodeI32(int32(startX)),
+		api.EncodeI32(int32(startY)),
+		api.EncodeI32(int32(sizeX)),
+		api.EncodeI32(int32(sizeY)),
+		api.EncodeI32(int32(rotate)),
+		api.EncodeI32(int32(flags)),
+	)
+
+	return err
+}
+
+// DrawFormFields draws the interactive form fields of the page into bitmap,
+// on top of what RenderPageBitmap rendered with the same arguments. This
+// initializes the form fill environment of the document.
+func (p *Page) DrawFormFields(ctx context.Context, bitmap *Bitmap, startX, startY, sizeX, sizeY, rotate, flags int) error {
+	form, err := p.document.formFillEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.document.instance.call(ctx, "FPDF_FFLDraw",
+		form.handle,
+		bitmap.handle,
+		p.handle,
+		api.EncodeI32(int32(startX)),
+		api.EncodeI32(int32(startY)),
+		api.EncodeI32(int32(sizeX)),
+		api.EncodeI32(int32(sizeY)),
+		api.EncodeI32(int32(rotate)),
+		api.EncodeI32(int32(flags)),
+	)
+
+	return err
+}
+
+// Close closes the page.
+func (p *Page) Close(ctx context.Context) error {
+	if p.handle == 0 {
+		return errors.New("pdfium: page is already closed")
+	}
+
+	var err error
+	if form := p.document.form; form != nil {
+		_, err = p.document.instance.call(ctx, "FORM_OnBeforeClosePage", p.handle, form.handle)
+	}
+
+	if _, closeErr := p.document.instance.call(ctx, "FPDF_ClosePage", p.handle); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	p.handle = 0
+
+	return err
+}
@@ -0,0 +1,237 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// PageBox is one of the boundary boxes of a page, see section 14.11.2 of the
+// PDF 1.7 specification.
+type PageBox int
+
+const (
+	PageBoxMedia PageBox = iota // MediaBox, the size of the medium the page is printed on.
+	PageBoxCrop                 // CropBox, the visible area of the page.
+	PageBoxBleed                // BleedBox, the area to clip to in a production environment.
+	PageBoxTrim                 // TrimBox, the size of the page after trimming.
+	PageBoxArt                  // ArtBox, the meaningful content of the page.
+)
+
+// name returns the name of the box in the FPDFPage_Get*Box functions.
+func (b PageBox) name() (string, error) {
+	switch b {
+	case PageBoxMedia:
+		return "Media", nil
+	case PageBoxCrop:
+		return "Crop", nil
+	case PageBoxBleed:
+		return "Bleed", nil
+	case PageBoxTrim:
+		return "Trim", nil
+	case PageBoxArt:
+		return "Art", nil
+	}
+
+	return "", fmt.Errorf("pdfium: invalid page box %d", b)
+}
+
+// Rotation returns the rotation of the page.
+func (p *Page) Rotation(ctx context.Context) (Rotation, error) {
+	rotation, err := p.document.instance.call1(ctx, "FPDFPage_GetRotation", p.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return Rotation(int32(rotation)), nil
+}
+
+// SetRotation sets the rotation of the page.
+func (p *Page) SetRotation(ctx context.Context, rotation Rotation) error {
+	if rotation < Rotation0 || rotation > Rotation270 {
+		return fmt.Errorf("pdfium: invalid rotation %d", rotation)
+	}
+
+	_, err := p.document.instance.call(ctx, "FPDFPage_SetRotation", p.handle, api.EncodeI32(int32(rotation)))
+	return err
+}
+
+// Box returns the box of the page, ok is false when it is not set.
+func (p *Page) Box(ctx context.Context, box PageBox) (rect Rect, ok bool, err error) {
+	i := p.document.instance
+
+	name, err := box.name()
+	if err != nil {
+		return Rect{}, false, err
+	}
+
+	// Four floats for left, bottom, right and top.
+	pointer, err := i.alloc(ctx, 16)
+	if err != nil {
+		return Rect{}, false, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, "FPDFPage_Get"+name+"Box", p.handle, pointer, pointer+4, pointer+8, pointer+12)
+	if err != nil {
+		return Rect{}, false, err
+	}
+
+	if success == 0 {
+		return Rect{}, false, nil
+	}
+
+	values, err := i.readFloat32s(ctx, pointer, 4)
+	if err != nil {
+		return Rect{}, false, err
+	}
+
+	return Rect{Left: float64(values[0]), Top: float64(values[3]), Right: float64(values[2]), Bottom: float64(values[1])}, true, nil
+}
+
+// SetBox sets the box of the page to rect.
+func (p *Page) SetBox(ctx context.Context, box PageBox, rect Rect) error {
+	name, err := box.name()
+	if err != nil {
+		return err
+	}
+
+	_, err = p.document.instance.call(ctx, "FPDFPage_Set"+name+"Box",
+		p.handle,
+		api.EncodeF32(float32(rect.Left)),
+		api.EncodeF32(float32(rect.Bottom)),
+		api.EncodeF32(float32(rect.Right)),
+		api.EncodeF32(float32(rect.Top)),
+	)
+
+	return err
+}
+
+// BoundingBox returns the visible area of the page, the intersection of its
+// MediaBox and CropBox.
+func (p *Page) BoundingBox(ctx context.Context) (Rect, error) {
+	i := p.document.instance
+
+	// An FS_RECTF.
+	values, err := i.callOutFloat32s(ctx, "FPDF_GetPageBoundingBox", 4, func(pointer uint64) []uint64 {
+		return []uint64{p.handle, pointer}
+	})
+	if err != nil {
+		return Rect{}, err
+	}
+
+	return Rect{Left: float64(values[0]), Top: float64(values[1]), Right: float64(values[2]), Bottom: float64(values[3])}, nil
+}
+
+// TransformWithClip transforms the content of the page by matrix and clips it
+// to clip, in the transformed coordinates. Either may be nil to leave it out.
+// The page content is rewritten, so GenerateContent is not needed.
+func (p *Page) TransformWithClip(ctx context.Context, matrix *Matrix, clip *Rect) error {
+	i := p.document.instance
+
+	var matrixPointer uint64
+	if matrix != nil {
+		var err error
+		matrixPointer, err = i.allocFloat32s(ctx, []float32{
+			float32(matrix.A),
+			float32(matrix.B),
+			float32(matrix.C),
+			float32(matrix.D),
+			float32(matrix.E),
+			float32(matrix.F),
+		})
+		if err != nil {
+			return err
+		}
+		defer i.release(ctx, matrixPointer)
+	}
+
+	var clipPointer uint64
+	if clip != nil {
+		var err error
+		clipPointer, err = i.allocFloat32s(ctx, []float32{float32(clip.Left), float32(clip.Top), float32(clip.Right), float32(clip.Bottom)})
+		if err != nil {
+			return err
+		}
+		defer i.release(ctx, clipPointer)
+	}
+
+	success, err := i.call1(ctx, "FPDFPage_TransFormWithClip", p.handle, matrixPointer, clipPointer)
+	if err != nil {
+		return err
+	}
+
+	if success == 0 {
+		return fmt.Errorf("pdfium: could not transform page %d", p.index)
+	}
+
+	return nil
+}
+
+// PageToDevice converts point, in page coordinates, to a pixel of the page
+// rendered with RenderPageBitmap and the same arguments.
+func (p *Page) PageToDevice(ctx context.Context, startX, startY, sizeX, sizeY, rotate int, point Point) (image.Point, error) {
+	i := p.document.instance
+
+	pointer, err := i.alloc(ctx, 8)
+	if err != nil {
+		return image.Point{}, err
+	}
+	defer i.release(ctx, pointer)
+
+	success, err := i.call1(ctx, "FPDF_PageToDevice",
+		p.handle,
+		api.EncodeI32(int32(startX)),
+		api.EncodeI32(int32(startY)),
+		api.EncodeI32(int32(sizeX)),
+		api.EncodeI32(int32(sizeY)),
+		api.EncodeI32(int32(rotate)),
+		api.EncodeF64(point.X),
+		api.EncodeF64(point.Y),
+		pointer,
+		pointer+4,
+	)
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	if success == 0 {
+		return image.Point{}, errors.New("pdfium: could not convert page to device coordinates")
+	}
+
+	values, err := i.readInt32s(ctx, pointer, 2)
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	return image.Point{X: int(values[0]), Y: int(values[1])}, nil
+}
+
+// DeviceToPage converts a pixel of the page rendered with RenderPageBitmap
+// and the same arguments to page coordinates.
+func (p *Page) DeviceToPage(ctx context.Context, startX, startY, sizeX, sizeY, rotate int, pixel image.Point) (Point, error) {
+	i := p.document.instance
+
+	values, err := i.callOutFloat64s(ctx, "FPDF_DeviceToPage", 2, func(pointer uint64) []uint64 {
+		return []uint64{
+			p.handle,
+			api.EncodeI32(int32(startX)),
+			api.EncodeI32(int32(startY)),
+			api.EncodeI32(int32(sizeX)),
+			api.EncodeI32(int32(sizeY)),
+			api.EncodeI32(int32(rotate)),
+			api.EncodeI32(int32(pixel.X)),
+			api.EncodeI32(int32(pixel.Y)),
+			pointer,
+			pointer + 8,
+		}
+	})
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: values[0], Y: values[1]}, nil
+}
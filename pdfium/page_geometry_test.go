@@ -0,0 +1,68 @@
+package pdfium
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPageBoxName(t *testing.T) {
+	tests := []struct {
+		box  PageBox
+		want string
+	}{
+		{PageBoxMedia, "Media"},
+		{PageBoxCrop, "Crop"},
+		{PageBoxBleed, "Bleed"},
+		{PageBoxTrim, "Trim"},
+		{PageBoxArt, "Art"},
+	}
+
+	for _, test := range tests {
+		got, err := test.box.name()
+		if err != nil || got != test.want {
+			t.Errorf("name() of box %d = %q, %v, want %q", test.box, got, err, test.want)
+		}
+	}
+
+	for _, box := range []PageBox{-1, PageBoxArt + 1} {
+		if _, err := box.name(); err == nil {
+			t.Errorf("name() of box %d error = nil, want an error", box)
+		}
+	}
+}
+
+func TestPageBox(t *testing.T) {
+	i := newTestInstance(t, map[string]testFunction{
+		"FPDFPage_GetCropBox": {
+			params:  i32s(5),
+			results: i32s(1),
+			body:    join(storeFloat32s(1, 10), storeFloat32s(2, 20), storeFloat32s(3, 30), storeFloat32s(4, 40), i32Const(1)),
+		},
+		"FPDFPage_GetArtBox": returnI32(i32s(5), 0),
+	})
+	p := &Page{document: &Document{instance: i, handle: 1}, handle: 1}
+	ctx := context.Background()
+
+	rect, ok, err := p.Box(ctx, PageBoxCrop)
+	if err != nil {
+		t.Fatalf("Box() error: %v", err)
+	}
+
+	if want := (Rect{Left: 10, Top: 40, Right: 30, Bottom: 20}); !ok || rect != want {
+		t.Errorf("Box() = %v, %v, want %v, true", rect, ok, want)
+	}
+
+	if _, ok, err := p.Box(ctx, PageBoxArt); ok || err != nil {
+		t.Errorf("Box() of a missing box = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestSetRotationInvalid(t *testing.T) {
+	p := &Page{document: &Document{}}
+
+	for _, rotation := range []Rotation{-1, Rotation270 + 1} {
+		if err := p.SetRotation(context.Background(), rotation); err == nil {
+			t.Errorf("SetRotation(%d) error = nil, want an error", rotation)
+		}
+	}
+}
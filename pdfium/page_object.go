@@ -0,0 +1,204 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// PageObjectType is the type of a page object.
+type PageObjectType int
+
+const (
+	PageObjectTypeUnknown PageObjectType = 0 // FPDF_PAGEOBJ_UNKNOWN
+	PageObjectTypeText    PageObjectType = 1 // FPDF_PAGEOBJ_TEXT
+	PageObjectTypePath    PageObjectType = 2 // FPDF_PAGEOBJ_PATH
+	PageObjectTypeImage   PageObjectType = 3 // FPDF_PAGEOBJ_IMAGE
+	PageObjectTypeShading PageObjectType = 4 // FPDF_PAGEOBJ_SHADING
+	PageObjectTypeForm    PageObjectType = 5 // FPDF_PAGEOBJ_FORM
+)
+
+// PageObject is an object in the content of a page.
+type PageObject struct {
+	// Path locates the object, it is the index of the object on the page
+	// followed by its index in each form XObject it is nested in.
+	Path []int
+
+	Type PageObjectType
+
+	// Bounds is the area of the object in page coordinates.
+	Bounds Rect
+
+	// Matrix is the transformation of the object. The objects of a form
+	// XObject are also transformed by the matrix of the form.
+	Matrix Matrix
+
+	// Children are the objects of a form XObject.
+	Children []PageObject
+}
+
+// Walk calls fn for the object and then for its children, depth first. It
+// stops at the first error fn returns.
+func (o *PageObject) Walk(fn func(object *PageObject) error) error {
+	if err := fn(o); err != nil {
+		return err
+	}
+
+	for n := range o.Children {
+		if err := o.Children[n].Walk(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ObjectCount returns the number of objects on the page, not counting the
+// objects in form XObjects.
+func (p *Page) ObjectCount(ctx context.Context) (int, error) {
+	count, err := p.document.instance.call1(ctx, "FPDFPage_CountObjects", p.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(int32(count)), nil
+}
+
+// Objects returns the objects on the page, with the objects of form XObjects
+// as their children.
+func (p *Page) Objects(ctx context.Context) ([]PageObject, error) {
+	count, err := p.ObjectCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]PageObject, 0, count)
+	for index := 0; index < count; index++ {
+		handle, err := p.document.instance.call1(ctx, "FPDFPage_GetObject", p.handle, api.EncodeI32(int32(index)))
+		if err != nil {
+			return nil, err
+		}
+
+		if handle == 0 {
+			return nil, fmt.Errorf("pdfium: page object %d not found", index)
+		}
+
+		object, err := p.pageObject(ctx, handle, []int{index})
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, *object)
+	}
+
+	return objects, nil
+}
+
+// pageObjectHandle returns the FPDF_PAGEOBJECT handle at path.
+func (p *Page) pageObjectHandle(ctx context.Context, path []int) (uint64, error) {
+	i := p.document.instance
+
+	if len(path) == 0 {
+		return 0, errors.New("pdfium: empty page object path")
+	}
+
+	handle, err := i.call1(ctx, "FPDFPage_GetObject", p.handle, api.EncodeI32(int32(path[0])))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, index := range path[1:] {
+		if handle == 0 {
+			break
+		}
+
+		if handle, err = i.call1(ctx, "FPDFFormObj_GetObject", handle, uint64(uint32(index))); err != nil {
+			return 0, err
+		}
+	}
+
+	if handle == 0 {
+		return 0, fmt.Errorf("pdfium: page object %v not found", path)
+	}
+
+	return handle, nil
+}
+
+// pageObject reads the FPDF_PAGEOBJECT handle at path and its children.
+func (p *Page) pageObject(ctx context.Context, handle uint64, path []int) (*PageObject, error) {
+	i := p.document.instance
+
+	objectType, err := i.call1(ctx, "FPDFPageObj_GetType", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	object := &PageObject{
+		Path: path,
+		Type: PageObjectType(int32(objectType)),
+	}
+
+	// FPDFPageObj_GetBounds returns left, bottom, right, top.
+	bounds, err := i.callOutFloat32s(ctx, "FPDFPageObj_GetBounds", 4, func(pointer uint64) []uint64 {
+		return []uint64{handle, pointer, pointer + 4, pointer + 8, pointer + 12}
+	})
+	if err != nil && !isFalse(err) {
+		return nil, err
+	}
+
+	// Bounds and Matrix stay zero when PDFium can't compute them.
+	if err == nil {
+		object.Bounds = Rect{Left: float64(bounds[0]), Top: float64(bounds[3]), Right: float64(bounds[2]), Bottom: float64(bounds[1])}
+	}
+
+	// An FS_MATRIX, a to f.
+	matrix, err := i.callOutFloat32s(ctx, "FPDFPageObj_GetMatrix", 6, func(pointer uint64) []uint64 {
+		return []uint64{handle, pointer}
+	})
+	if err != nil && !isFalse(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		object.Matrix = Matrix{
+			A: float64(matrix[0]),
+			B: float64(matrix[1]),
+			C: float64(matrix[2]),
+			D: float64(matrix[3]),
+			E: float64(matrix[4]),
+			F: float64(matrix[5]),
+		}
+	}
+
+	if object.Type != PageObjectTypeForm {
+		return object, nil
+	}
+
+	count, err := i.call1(ctx, "FPDFFormObj_CountObjects", handle)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := 0; index < int(int32(count)); index++ {
+		childHandle, err := i.call1(ctx, "FPDFFormObj_GetObject", handle, uint64(index))
+		if err != nil {
+			return nil, err
+		}
+
+		if childHandle == 0 {
+			continue
+		}
+
+		childPath := append(path[:len(path):len(path)], index)
+		child, err := p.pageObject(ctx, childHandle, childPath)
+		if err != nil {
+			return nil, err
+		}
+
+		object.Children = append(object.Children, *child)
+	}
+
+	return object, nil
+}
@@ -0,0 +1,52 @@
+package pdfium
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPageObjectWalk(t *testing.T) {
+	object := &PageObject{
+		Path: []int{0},
+		Children: []PageObject{
+			{Path: []int{0, 0}, Children: []PageObject{{Path: []int{0, 0, 0}}}},
+			{Path: []int{0, 1}},
+		},
+	}
+
+	var paths []string
+	err := object.Walk(func(object *PageObject) error {
+		paths = append(paths, fmt.Sprint(object.Path))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error: %v", err)
+	}
+
+	if want := []string{"[0]", "[0 0]", "[0 0 0]", "[0 1]"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Walk() visited %v, want %v", paths, want)
+	}
+}
+
+func TestPageObjectWalkStops(t *testing.T) {
+	object := &PageObject{
+		Path:     []int{0},
+		Children: []PageObject{{Path: []int{0, 0}}, {Path: []int{0, 1}}},
+	}
+
+	stop := errors.New("stop")
+	visited := 0
+	err := object.Walk(func(object *PageObject) error {
+		visited++
+		if len(object.Path) == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop || visited != 2 {
+		t.Errorf("Walk() = %v after %d objects, want %v after 2", err, visited, stop)
+	}
+}
@@ -0,0 +1,280 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned when checking out an instance of a closed pool.
+var ErrPoolClosed = errors.New("pdfium: pool is closed")
+
+// ErrNotCheckedOut is returned when an instance is returned to a pool it is
+// not checked out of, because it was already returned or belongs to another
+// pool.
+var ErrNotCheckedOut = errors.New("pdfium: instance is not checked out of the pool")
+
+// PoolConfig configures a Pool.
+type PoolConfig struct {
+	// MinIdle is the number of idle instances kept around, they are created
+	// when the pool is created and never evicted.
+	MinIdle int
+
+	// MaxTotal is the maximum number of instances, idle and checked out. Get
+	// blocks while all of them are checked out. Defaults to 1.
+	MaxTotal int
+
+	// IdleTimeout is how long an instance above MinIdle may stay idle before
+	// it is closed. Zero disables eviction.
+	IdleTimeout time.Duration
+}
+
+type idleInstance struct {
+	instance *Instance
+	since    time.Time
+}
+
+// Pool dispatches work over independent instances of one Runtime. Every
+// instance has its own linear memory and PDFium library, so instances can be
+// used from different goroutines at the same time while a single instance is
+// only ever used by one.
+type Pool struct {
+	runtime *Runtime
+	config  PoolConfig
+
+	// tokens holds one token per instance that may be checked out.
+	tokens chan struct{}
+
+	lock       sync.Mutex
+	idle       []idleInstance
+	checkedOut map[*Instance]bool
+	closed     bool
+
+	stopEviction chan struct{}
+	evictionDone chan struct{}
+}
+
+// NewPool creates a pool of instances of runtime.
+func NewPool(ctx context.Context, runtime *Runtime, config PoolConfig) (*Pool, error) {
+	if config.MaxTotal <= 0 {
+		config.MaxTotal = 1
+	}
+
+	if config.MinIdle < 0 || config.MinIdle > config.MaxTotal {
+		return nil, fmt.Errorf("pdfium: MinIdle must be between 0 and MaxTotal (%d), got %d", config.MaxTotal, config.MinIdle)
+	}
+
+	p := &Pool{
+		runtime:    runtime,
+		config:     config,
+		tokens:     make(chan struct{}, config.MaxTotal),
+		checkedOut: map[*Instance]bool{},
+	}
+
+	for i := 0; i < config.MaxTotal; i++ {
+		p.tokens <- struct{}{}
+	}
+
+	for i := 0; i < config.MinIdle; i++ {
+		instance, err := runtime.NewInstance(ctx)
+		if err != nil {
+			p.Close(ctx)
+			return nil, err
+		}
+
+		p.idle = append(p.idle, idleInstance{instance: instance, since: time.Now()})
+	}
+
+	if config.IdleTimeout > 0 {
+		p.stopEviction = make(chan struct{})
+		p.evictionDone = make(chan struct{})
+		go p.evictLoop()
+	}
+
+	return p, nil
+}
+
+// Get checks out an instance, creating one when none is idle. It blocks until
+// an instance is available or ctx is done. The instance must be returned with
+// Put or Discard.
+func (p *Pool) Get(ctx context.Context) (*Instance, error) {
+	select {
+	case <-p.tokens:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		p.tokens <- struct{}{}
+		return nil, ErrPoolClosed
+	}
+
+	if n := len(p.idle); n > 0 {
+		// Take the most recently used instance, so the others can be evicted.
+		instance := p.idle[n-1].instance
+		p.idle = p.idle[:n-1]
+		p.checkedOut[instance] = true
+		p.lock.Unlock()
+		return instance, nil
+	}
+	p.lock.Unlock()
+
+	instance, err := p.runtime.NewInstance(ctx)
+	if err != nil {
+		p.tokens <- struct{}{}
+		return nil, err
+	}
+
+	p.lock.Lock()
+	p.checkedOut[instance] = true
+	p.lock.Unlock()
+
+	return instance, nil
+}
+
+// Put returns an instance that was checked out with Get. Every document of
+// the instance must be closed. It returns ErrNotCheckedOut when the instance
+// was already returned or is not from this pool.
+func (p *Pool) Put(ctx context.Context, instance *Instance) error {
+	p.lock.Lock()
+	if !p.checkedOut[instance] {
+		p.lock.Unlock()
+		return ErrNotCheckedOut
+	}
+	delete(p.checkedOut, instance)
+
+	if p.closed {
+		p.lock.Unlock()
+		p.tokens <- struct{}{}
+		return instance.Close(ctx)
+	}
+
+	p.idle = append(p.idle, idleInstance{instance: instance, since: time.Now()})
+	p.lock.Unlock()
+	p.tokens <- struct{}{}
+
+	return nil
+}
+
+// Discard closes an instance that was checked out with Get instead of
+// returning it, for example after a call into it failed and its state can no
+// longer be trusted. It returns ErrNotCheckedOut, without closing the
+// instance, when the instance was already returned or is not from this pool.
+func (p *Pool) Discard(ctx context.Context, instance *Instance) error {
+	p.lock.Lock()
+	if !p.checkedOut[instance] {
+		p.lock.Unlock()
+		return ErrNotCheckedOut
+	}
+	delete(p.checkedOut, instance)
+	p.lock.Unlock()
+
+	err := instance.Close(ctx)
+	p.tokens <- struct{}{}
+	return err
+}
+
+// Do checks out an instance, calls fn with it and returns it to the pool. The
+// instance is discarded when fn panics or returns a *CallError.
+func (p *Pool) Do(ctx context.Context, fn func(instance *Instance) error) error {
+	instance, err := p.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.Discard(ctx, instance)
+			panic(r)
+		}
+	}()
+
+	fnErr := fn(instance)
+
+	var callErr *CallError
+	if errors.As(fnErr, &callErr) {
+		p.Discard(ctx, instance)
+		return fnErr
+	}
+
+	if err := p.Put(ctx, instance); err != nil && fnErr == nil {
+		return err
+	}
+
+	return fnErr
+}
+
+// Close closes every idle instance. Instances that are checked out are closed
+// when they are returned.
+func (p *Pool) Close(ctx context.Context) error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.lock.Unlock()
+
+	if p.stopEviction != nil {
+		close(p.stopEviction)
+		<-p.evictionDone
+	}
+
+	var err error
+	for _, idleInstance := range idle {
+		if closeErr := idleInstance.instance.Close(ctx); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func (p *Pool) evictLoop() {
+	defer close(p.evictionDone)
+
+	interval := p.config.IdleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopEviction:
+			return
+		case now := <-ticker.C:
+			p.evict(now)
+		}
+	}
+}
+
+// evict closes the instances above MinIdle that have been idle for longer
+// than IdleTimeout.
+func (p *Pool) evict(now time.Time) {
+	p.lock.Lock()
+	var expired []*Instance
+	kept := p.idle[:0]
+	for _, idleInstance := range p.idle {
+		// The idle list is ordered from least to most recently used.
+		if len(p.idle)-len(expired) > p.config.MinIdle && now.Sub(idleInstance.since) > p.config.IdleTimeout {
+			expired = append(expired, idleInstance.instance)
+			continue
+		}
+		kept = append(kept, idleInstance)
+	}
+	p.idle = kept
+	p.lock.Unlock()
+
+	for _, instance := range expired {
+		instance.Close(context.Background())
+	}
+}
@@ -0,0 +1,286 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// idleCount returns the number of idle instances of the pool.
+func idleCount(p *Pool) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.idle)
+}
+
+func TestNewPoolInvalidMinIdle(t *testing.T) {
+	runtime := newTestRuntime(t, nil)
+
+	for _, config := range []PoolConfig{{MinIdle: -1}, {MinIdle: 3, MaxTotal: 2}, {MinIdle: 2}} {
+		if _, err := NewPool(context.Background(), runtime, config); err == nil {
+			t.Errorf("NewPool(%+v) succeeded, want an error", config)
+		}
+	}
+}
+
+func TestPoolMinIdle(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewPool(ctx, newTestRuntime(t, nil), PoolConfig{MinIdle: 2, MaxTotal: 3})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+	defer pool.Close(ctx)
+
+	if n := idleCount(pool); n != 2 {
+		t.Errorf("NewPool() created %d idle instances, want 2", n)
+	}
+}
+
+func TestPoolTokens(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewPool(ctx, newTestRuntime(t, nil), PoolConfig{MaxTotal: 2})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+	defer pool.Close(ctx)
+
+	first, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+
+	second, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+
+	if first == second {
+		t.Fatal("Get() returned the same instance twice")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := pool.Get(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get() with every instance checked out error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if err := pool.Put(ctx, second); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	again, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() after Put() error: %v", err)
+	}
+
+	if again != second {
+		t.Error("Get() after Put() did not reuse the idle instance")
+	}
+
+	if err := pool.Discard(ctx, again); err != nil {
+		t.Fatalf("Discard() error: %v", err)
+	}
+
+	if _, err := pool.Get(ctx); err != nil {
+		t.Errorf("Get() after Discard() error: %v", err)
+	}
+}
+
+func TestPoolEvict(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewPool(ctx, newTestRuntime(t, nil), PoolConfig{MinIdle: 1, MaxTotal: 3, IdleTimeout: time.Hour})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+	defer pool.Close(ctx)
+
+	instances := make([]*Instance, 3)
+	for n := range instances {
+		if instances[n], err = pool.Get(ctx); err != nil {
+			t.Fatalf("Get() error: %v", err)
+		}
+	}
+
+	for _, instance := range instances {
+		if err := pool.Put(ctx, instance); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+
+	pool.evict(time.Now())
+	if n := idleCount(pool); n != 3 {
+		t.Fatalf("evict() before IdleTimeout left %d idle instances, want 3", n)
+	}
+
+	pool.evict(time.Now().Add(2 * time.Hour))
+	if n := idleCount(pool); n != 1 {
+		t.Fatalf("evict() after IdleTimeout left %d idle instances, want MinIdle", n)
+	}
+
+	if pool.idle[0].instance != instances[2] {
+		t.Error("evict() did not keep the most recently used instance")
+	}
+}
+
+func TestPoolDoDiscardsOnPanic(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewPool(ctx, newTestRuntime(t, nil), PoolConfig{})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+	defer pool.Close(ctx)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Do() did not pass on the panic")
+			}
+		}()
+
+		pool.Do(ctx, func(instance *Instance) error {
+			panic("boom")
+		})
+	}()
+
+	if n := idleCount(pool); n != 0 {
+		t.Errorf("Do() kept %d instances after a panic, want 0", n)
+	}
+
+	// The token of the discarded instance must be back.
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if err := pool.Do(timeoutCtx, func(instance *Instance) error { return nil }); err != nil {
+		t.Errorf("Do() after a panic error: %v", err)
+	}
+
+	if n := idleCount(pool); n != 1 {
+		t.Errorf("Do() kept %d instances, want 1", n)
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewPool(ctx, newTestRuntime(t, nil), PoolConfig{MinIdle: 1, MaxTotal: 2, IdleTimeout: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+
+	instance, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+
+	if err := pool.Close(ctx); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	if n := idleCount(pool); n != 0 {
+		t.Errorf("Close() kept %d idle instances, want 0", n)
+	}
+
+	if _, err := pool.Get(ctx); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Get() after Close() error = %v, want ErrPoolClosed", err)
+	}
+
+	if err := pool.Put(ctx, instance); err != nil {
+		t.Errorf("Put() after Close() error: %v", err)
+	}
+
+	if n := idleCount(pool); n != 0 {
+		t.Errorf("Put() after Close() kept the instance idle")
+	}
+
+	if err := pool.Close(ctx); err != nil {
+		t.Errorf("second Close() error: %v", err)
+	}
+}
+
+func TestPoolDoDiscardsOnCallError(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewPool(ctx, newTestRuntime(t, map[string]testFunction{
+		"trap": trap(nil, nil),
+	}), PoolConfig{})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+	defer pool.Close(ctx)
+
+	err = pool.Do(ctx, func(instance *Instance) error {
+		_, err := instance.call(ctx, "trap")
+		return err
+	})
+
+	var callErr *CallError
+	if !errors.As(err, &callErr) {
+		t.Fatalf("Do() error = %v, want the *CallError of fn", err)
+	}
+
+	if n := idleCount(pool); n != 0 {
+		t.Errorf("Do() kept %d instances after a *CallError, want 0", n)
+	}
+
+	fnErr := errors.New("not a call error")
+	if err := pool.Do(ctx, func(instance *Instance) error { return fnErr }); err != fnErr {
+		t.Errorf("Do() error = %v, want the error of fn", err)
+	}
+
+	if n := idleCount(pool); n != 1 {
+		t.Errorf("Do() kept %d instances after another error, want 1", n)
+	}
+}
+
+func TestPoolReturnNotCheckedOut(t *testing.T) {
+	ctx := context.Background()
+	runtime := newTestRuntime(t, nil)
+	pool, err := NewPool(ctx, runtime, PoolConfig{})
+	if err != nil {
+		t.Fatalf("NewPool() error: %v", err)
+	}
+	defer pool.Close(ctx)
+
+	instance, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+
+	if err := pool.Put(ctx, instance); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	if err := pool.Put(ctx, instance); !errors.Is(err, ErrNotCheckedOut) {
+		t.Errorf("second Put() error = %v, want ErrNotCheckedOut", err)
+	}
+
+	if err := pool.Discard(ctx, instance); !errors.Is(err, ErrNotCheckedOut) {
+		t.Errorf("Discard() after Put() error = %v, want ErrNotCheckedOut", err)
+	}
+
+	other, err := runtime.NewInstance(ctx)
+	if err != nil {
+		t.Fatalf("NewInstance() error: %v", err)
+	}
+	defer other.Close(ctx)
+
+	if err := pool.Put(ctx, other); !errors.Is(err, ErrNotCheckedOut) {
+		t.Errorf("Put() of another instance error = %v, want ErrNotCheckedOut", err)
+	}
+
+	if err := pool.Discard(ctx, other); !errors.Is(err, ErrNotCheckedOut) {
+		t.Errorf("Discard() of another instance error = %v, want ErrNotCheckedOut", err)
+	}
+
+	// The pool still has a single instance to check out.
+	if _, err := pool.Get(ctx); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := pool.Get(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() of a second instance error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,352 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// RenderFlags is a combination of the FPDF_* render flags.
+type RenderFlags int
+
+const (
+	RenderFlagAnnotations         RenderFlags = 0x01   // FPDF_ANNOT, render annotations.
+	RenderFlagLCDText             RenderFlags = 0x02   // FPDF_LCD_TEXT, optimize text for LCD displays.
+	RenderFlagNoNativeText        RenderFlags = 0x04   // FPDF_NO_NATIVETEXT, don't use the native text output.
+	RenderFlagGrayscale           RenderFlags = 0x08   // FPDF_GRAYSCALE, render in grayscale.
+	RenderFlagConvertFillToStroke RenderFlags = 0x20   // FPDF_CONVERT_FILL_TO_STROKE, render thin filled paths as strokes.
+	RenderFlagLimitedImageCache   RenderFlags = 0x200  // FPDF_RENDER_LIMITEDIMAGECACHE, limit the image cache size.
+	RenderFlagForceHalftone       RenderFlags = 0x400  // FPDF_RENDER_FORCEHALFTONE, always use halftone for image stretching.
+	RenderFlagPrinting            RenderFlags = 0x800  // FPDF_PRINTING, render for printing.
+	RenderFlagNoSmoothText        RenderFlags = 0x1000 // FPDF_RENDER_NO_SMOOTHTEXT, disable anti-aliasing on text.
+	RenderFlagNoSmoothImage       RenderFlags = 0x2000 // FPDF_RENDER_NO_SMOOTHIMAGE, disable anti-aliasing on images.
+	RenderFlagNoSmoothPath        RenderFlags = 0x4000 // FPDF_RENDER_NO_SMOOTHPATH, disable anti-aliasing on paths.
+)
+
+// renderFlagReverseByteOrder is FPDF_REVERSE_BYTE_ORDER. It is never passed
+// to PDFium, the conversion to RGBA is done by RenderPage itself.
+const renderFlagReverseByteOrder RenderFlags = 0x10
+
+// Rotation is a clockwise rotation in quarter turns.
+type Rotation int
+
+const (
+	Rotation0   Rotation = 0
+	Rotation90  Rotation = 1
+	Rotation180 Rotation = 2
+	Rotation270 Rotation = 3
+)
+
+// RenderOptions configures how a page is rendered to an image. The size of
+// the image is set by at most one of Width/Height, DPI, MaxWidth/MaxHeight
+// and Scale. When none is set the page is rendered at 72 DPI, one pixel per
+// point.
+type RenderOptions struct {
+	// Width and Height set the size of the image in pixels. When only one of
+	// them is set, the other follows from the aspect ratio of the page.
+	Width  int
+	Height int
+
+	// DPI sets the resolution of the image in dots per inch.
+	DPI float64
+
+	// MaxWidth and MaxHeight scale the page to the largest size that fits
+	// within them while keeping its aspect ratio. Either may be left zero.
+	MaxWidth  int
+	MaxHeight int
+
+	// Scale sets the number of pixels per point.
+	Scale float64
+
+	// Rotation rotates the page clockwise. The size of the image is computed
+	// after rotating.
+	Rotation Rotation
+
+	// Flags are the PDFium render flags.
+	Flags RenderFlags
+
+	// BackgroundColor fills the image before the page is rendered on top of
+	// it. Defaults to opaque white.
+	BackgroundColor color.Color
+
+	// FormFields draws the interactive form fields on top of the page, with
+	// their current values. This initializes the form fill environment of
+	// the document.
+	FormFields bool
+
+	// FormFieldHighlightColor highlights the form fields when FormFields is
+	// set, its alpha is the opacity of the highlight. Form fields are not
+	// highlighted when it is nil.
+	FormFieldHighlightColor color.Color
+}
+
+// size returns the size in pixels of the image of a page that is
+// pageWidth x pageHeight points, before rotating.
+func (o RenderOptions) size(pageWidth, pageHeight float64) (int, int, error) {
+	if o.Rotation < Rotation0 || o.Rotation > Rotation270 {
+		return 0, 0, fmt.Errorf("pdfium: invalid rotation %d", o.Rotation)
+	}
+
+	if o.Rotation == Rotation90 || o.Rotation == Rotation270 {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+
+	if pageWidth <= 0 || pageHeight <= 0 {
+		return 0, 0, fmt.Errorf("pdfium: invalid page size %gx%g", pageWidth, pageHeight)
+	}
+
+	modes := 0
+	if o.Width > 0 || o.Height > 0 {
+		modes++
+	}
+	if o.DPI > 0 {
+		modes++
+	}
+	if o.MaxWidth > 0 || o.MaxHeight > 0 {
+		modes++
+	}
+	if o.Scale > 0 {
+		modes++
+	}
+	if modes > 1 {
+		return 0, 0, errors.New("pdfium: only one of Width/Height, DPI, MaxWidth/MaxHeight and Scale can be set")
+	}
+
+	scale := 1.0
+	switch {
+	case o.Width > 0 && o.Height > 0:
+		return o.Width, o.Height, nil
+	case o.Width > 0:
+		scale = float64(o.Width) / pageWidth
+	case o.Height > 0:
+		scale = float64(o.Height) / pageHeight
+	case o.DPI > 0:
+		scale = o.DPI / 72
+	case o.MaxWidth > 0 || o.MaxHeight > 0:
+		scale = math.Inf(1)
+		if o.MaxWidth > 0 {
+			scale = float64(o.MaxWidth) / pageWidth
+		}
+		if o.MaxHeight > 0 {
+			scale = math.Min(scale, float64(o.MaxHeight)/pageHeight)
+		}
+	case o.Scale > 0:
+		scale = o.Scale
+	}
+
+	width := int(math.Round(pageWidth * scale))
+	height := int(math.Round(pageHeight * scale))
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	return width, height, nil
+}
+
+// backgroundColor returns the background color as 0xAARRGGBB.
+func (o RenderOptions) backgroundColor() uint32 {
+	if o.BackgroundColor == nil {
+		return 0xFFFFFFFF
+	}
+
+	c := color.NRGBAModel.Convert(o.BackgroundColor).(color.NRGBA)
+	return uint32(c.A)<<24 | uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
+
+// formFieldHighlight returns the form field highlight color as 0xFFRRGGBB
+// and its alpha, ok is false when form fields should not be highlighted.
+func (o RenderOptions) formFieldHighlight() (rgb uint32, alpha uint8, ok bool) {
+	if o.FormFieldHighlightColor == nil {
+		return 0, 0, false
+	}
+
+	c := color.NRGBAModel.Convert(o.FormFieldHighlightColor).(color.NRGBA)
+	return 0xFF000000 | uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B), c.A, true
+}
+
+// ImageView is a rendered page whose pixels live in linear memory. Image is
+// only valid until Release is called or the instance is used again, since
+// PDFium may grow the memory and move it.
+type ImageView struct {
+	Image *image.RGBA
+
+	bitmap *Bitmap
+}
+
+// Release destroys the bitmap behind the view and releases its buffer.
+func (v *ImageView) Release(ctx context.Context) error {
+	v.Image = nil
+	return v.bitmap.Destroy(ctx)
+}
+
+// RenderPage renders page to an image that is owned by Go.
+func (i *Instance) RenderPage(ctx context.Context, page *Page, options RenderOptions) (*image.RGBA, error) {
+	bitmap, err := i.renderPage(ctx, page, options)
+	if err != nil {
+		return nil, err
+	}
+	defer bitmap.Destroy(ctx)
+
+	pix, err := i.view(ctx, bitmap.bufferPointer, uint64(bitmap.stride*bitmap.height))
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, bitmap.width, bitmap.height))
+	bgraToRGBA(img.Pix, pix)
+
+	return img, nil
+}
+
+// RenderPageView renders page without copying the pixels out of linear
+// memory. The view must be released when it is no longer used.
+func (i *Instance) RenderPageView(ctx context.Context, page *Page, options RenderOptions) (*ImageView, error) {
+	bitmap, err := i.renderPage(ctx, page, options)
+	if err != nil {
+		return nil, err
+	}
+
+	pix, err := i.view(ctx, bitmap.bufferPointer, uint64(bitmap.stride*bitmap.height))
+	if err != nil {
+		bitmap.Destroy(ctx)
+		return nil, err
+	}
+
+	// Convert in place, the buffer is not used by PDFium anymore.
+	bgraToRGBA(pix, pix)
+
+	return &ImageView{
+		Image: &image.RGBA{
+			Pix:    pix,
+			Stride: bitmap.stride,
+			Rect:   image.Rect(0, 0, bitmap.width, bitmap.height),
+		},
+		bitmap: bitmap,
+	}, nil
+}
+
+// RenderSize returns the size in pixels of the image RenderPage creates for
+// page with options.
+func (i *Instance) RenderSize(ctx context.Context, page *Page, options RenderOptions) (int, int, error) {
+	pageWidth, err := page.Width(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pageHeight, err := page.Height(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return options.size(float64(pageWidth), float64(pageHeight))
+}
+
+// ImageRect returns the rectangle in the image RenderPage creates for page
+// with options that covers rect, which is in page coordinates.
+func (i *Instance) ImageRect(ctx context.Context, page *Page, options RenderOptions, rect Rect) (image.Rectangle, error) {
+	topLeft, err := i.PageToImage(ctx, page, options, Point{X: rect.Left, Y: rect.Top})
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+
+	bottomRight, err := i.PageToImage(ctx, page, options, Point{X: rect.Right, Y: rect.Bottom})
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+
+	// Rectangle.Canon fixes up the corners when the page was rotated.
+	return image.Rectangle{Min: topLeft, Max: bottomRight}.Canon(), nil
+}
+
+// PageToImage converts point, in page coordinates, to the pixel it lands on
+// in the image RenderPage creates for page with options.
+func (i *Instance) PageToImage(ctx context.Context, page *Page, options RenderOptions, point Point) (image.Point, error) {
+	width, height, err := i.RenderSize(ctx, page, options)
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	return page.PageToDevice(ctx, 0, 0, width, height, int(options.Rotation), point)
+}
+
+// ImageToPage converts a pixel of the image RenderPage creates for page with
+// options to page coordinates.
+func (i *Instance) ImageToPage(ctx context.Context, page *Page, options RenderOptions, pixel image.Point) (Point, error) {
+	width, height, err := i.RenderSize(ctx, page, options)
+	if err != nil {
+		return Point{}, err
+	}
+
+	return page.DeviceToPage(ctx, 0, 0, width, height, int(options.Rotation), pixel)
+}
+
+// renderPage renders page into a new BGRA bitmap.
+func (i *Instance) renderPage(ctx context.Context, page *Page, options RenderOptions) (*Bitmap, error) {
+	width, height, err := i.RenderSize(ctx, page, options)
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap, err := i.NewBitmap(ctx, width, height, BitmapFormatBGRA)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bitmap.FillRect(ctx, 0, 0, width, height, options.backgroundColor()); err != nil {
+		bitmap.Destroy(ctx)
+		return nil, err
+	}
+
+	flags := options.Flags &^ renderFlagReverseByteOrder
+	if err := page.RenderPageBitmap(ctx, bitmap, 0, 0, width, height, int(options.Rotation), int(flags)); err != nil {
+		bitmap.Destroy(ctx)
+		return nil, err
+	}
+
+	if options.FormFields {
+		if err := page.renderFormFields(ctx, bitmap, width, height, options, flags); err != nil {
+			bitmap.Destroy(ctx)
+			return nil, err
+		}
+	}
+
+	return bitmap, nil
+}
+
+// renderFormFields draws the form fields of page on top of the page in
+// bitmap.
+func (p *Page) renderFormFields(ctx context.Context, bitmap *Bitmap, width, height int, options RenderOptions, flags RenderFlags) error {
+	form, err := p.document.formFillEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The highlight is a setting of the form fill environment, so it is set
+	// for every render.
+	if err := form.setHighlight(ctx, options); err != nil {
+		return err
+	}
+
+	return p.DrawFormFields(ctx, bitmap, 0, 0, width, height, int(options.Rotation), int(flags))
+}
+
+// bgraToRGBA converts the non-premultiplied BGRA pixels in src to the
+// premultiplied RGBA pixels image.RGBA expects. dst and src may be the same
+// slice.
+func bgraToRGBA(dst, src []byte) {
+	for p := 0; p+3 < len(src); p += 4 {
+		b, g, r, a := src[p], src[p+1], src[p+2], src[p+3]
+		if a != 0xff {
+			r = byte(uint16(r) * uint16(a) / 0xff)
+			g = byte(uint16(g) * uint16(a) / 0xff)
+			b = byte(uint16(b) * uint16(a) / 0xff)
+		}
+		dst[p], dst[p+1], dst[p+2], dst[p+3] = r, g, b, a
+	}
+}
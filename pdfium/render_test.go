@@ -0,0 +1,133 @@
+package pdfium
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestRenderOptionsSize(t *testing.T) {
+	// A US Letter page.
+	const pageWidth, pageHeight = 612, 792
+
+	tests := []struct {
+		name    string
+		options RenderOptions
+		width   int
+		height  int
+		wantErr bool
+	}{
+		{name: "default", options: RenderOptions{}, width: 612, height: 792},
+		{name: "dpi", options: RenderOptions{DPI: 144}, width: 1224, height: 1584},
+		{name: "width", options: RenderOptions{Width: 306}, width: 306, height: 396},
+		{name: "height", options: RenderOptions{Height: 396}, width: 306, height: 396},
+		{name: "width and height", options: RenderOptions{Width: 100, Height: 100}, width: 100, height: 100},
+		{name: "max width", options: RenderOptions{MaxWidth: 306}, width: 306, height: 396},
+		{name: "max height", options: RenderOptions{MaxHeight: 396}, width: 306, height: 396},
+		{name: "max width and height", options: RenderOptions{MaxWidth: 306, MaxHeight: 300}, width: 232, height: 300},
+		{name: "scale", options: RenderOptions{Scale: 2}, width: 1224, height: 1584},
+		{name: "tiny scale", options: RenderOptions{Scale: 0.0001}, width: 1, height: 1},
+		{name: "rotation 90", options: RenderOptions{Rotation: Rotation90}, width: 792, height: 612},
+		{name: "rotation 180", options: RenderOptions{Rotation: Rotation180}, width: 612, height: 792},
+		{name: "rotation 270 with width", options: RenderOptions{Rotation: Rotation270, Width: 396}, width: 396, height: 306},
+		{name: "rotation 90 with max height", options: RenderOptions{Rotation: Rotation90, MaxHeight: 306}, width: 396, height: 306},
+		{name: "invalid rotation", options: RenderOptions{Rotation: 4}, wantErr: true},
+		{name: "width and dpi", options: RenderOptions{Width: 100, DPI: 72}, wantErr: true},
+		{name: "height and scale", options: RenderOptions{Height: 100, Scale: 1}, wantErr: true},
+		{name: "dpi and max width", options: RenderOptions{DPI: 72, MaxWidth: 100}, wantErr: true},
+		{name: "max height and scale", options: RenderOptions{MaxHeight: 100, Scale: 1}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			width, height, err := test.options.size(pageWidth, pageHeight)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("size() = %dx%d, want an error", width, height)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("size() error: %v", err)
+			}
+
+			if width != test.width || height != test.height {
+				t.Errorf("size() = %dx%d, want %dx%d", width, height, test.width, test.height)
+			}
+		})
+	}
+}
+
+func TestRenderOptionsSizeInvalidPage(t *testing.T) {
+	if _, _, err := (RenderOptions{}).size(0, 792); err == nil {
+		t.Error("size() with an empty page succeeded, want an error")
+	}
+}
+
+func TestRenderOptionsBackgroundColor(t *testing.T) {
+	tests := []struct {
+		color color.Color
+		want  uint32
+	}{
+		{nil, 0xFFFFFFFF},
+		{color.Black, 0xFF000000},
+		{color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0x78}, 0x78123456},
+		{color.Transparent, 0x00000000},
+	}
+
+	for _, test := range tests {
+		if got := (RenderOptions{BackgroundColor: test.color}).backgroundColor(); got != test.want {
+			t.Errorf("backgroundColor() of %v = %#08x, want %#08x", test.color, got, test.want)
+		}
+	}
+}
+
+func TestRenderOptionsFormFieldHighlight(t *testing.T) {
+	if _, _, ok := (RenderOptions{}).formFieldHighlight(); ok {
+		t.Error("formFieldHighlight() without a color is ok, want not ok")
+	}
+
+	rgb, alpha, ok := RenderOptions{FormFieldHighlightColor: color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0x78}}.formFieldHighlight()
+	if !ok || rgb != 0xFF123456 || alpha != 0x78 {
+		t.Errorf("formFieldHighlight() = %#08x, %#02x, %v, want 0xff123456, 0x78, true", rgb, alpha, ok)
+	}
+}
+
+func TestBGRAToRGBA(t *testing.T) {
+	tests := []struct {
+		name string
+		src  []byte
+		want []byte
+	}{
+		{name: "opaque", src: []byte{10, 20, 30, 255}, want: []byte{30, 20, 10, 255}},
+		{name: "transparent", src: []byte{10, 20, 30, 0}, want: []byte{0, 0, 0, 0}},
+		{name: "half transparent", src: []byte{255, 128, 64, 128}, want: []byte{32, 64, 128, 128}},
+		{name: "white at quarter alpha", src: []byte{255, 255, 255, 64}, want: []byte{64, 64, 64, 64}},
+		{
+			name: "several pixels",
+			src:  []byte{1, 2, 3, 255, 255, 255, 255, 0, 0, 0, 255, 255},
+			want: []byte{3, 2, 1, 255, 0, 0, 0, 0, 255, 0, 0, 255},
+		},
+		{name: "trailing bytes", src: []byte{1, 2, 3, 255, 9, 9}, want: []byte{3, 2, 1, 255, 0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := make([]byte, len(test.src))
+			bgraToRGBA(dst, test.src)
+			if !bytes.Equal(dst, test.want) {
+				t.Errorf("bgraToRGBA(%v) = %v, want %v", test.src, dst, test.want)
+			}
+		})
+	}
+}
+
+func TestBGRAToRGBAInPlace(t *testing.T) {
+	pix := []byte{255, 128, 64, 128}
+	bgraToRGBA(pix, pix)
+
+	if want := []byte{32, 64, 128, 128}; !bytes.Equal(pix, want) {
+		t.Errorf("bgraToRGBA in place = %v, want %v", pix, want)
+	}
+}
@@ -0,0 +1,101 @@
+package pdfium
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync/atomic"
+
+	"jerbob92/go-pdfium-wasm/imports"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Config configures the instances created by a Runtime.
+type Config struct {
+	// Stdout receives everything PDFium writes to stdout. Defaults to discarding it.
+	Stdout io.Writer
+
+	// Stderr receives everything PDFium writes to stderr. Defaults to discarding it.
+	Stderr io.Writer
+
+	// FS is the filesystem PDFium can read from, used by Instance.LoadDocument.
+	FS fs.FS
+}
+
+// Runtime holds a wazero runtime with the PDFium module compiled into it.
+// Compiling is the expensive part, so one Runtime can create any number of
+// instances, each with its own linear memory.
+type Runtime struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	config   Config
+
+	instanceCounter uint32
+}
+
+// NewRuntime creates a new wazero runtime, instantiates the host modules
+// PDFium depends on and compiles the given PDFium wasm binary.
+func NewRuntime(ctx context.Context, pdfiumWasm []byte, config Config) (*Runtime, error) {
+	r := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigCompiler())
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		r.Close(ctx)
+		return nil, fmt.Errorf("pdfium: could not instantiate wasi: %w", err)
+	}
+
+	// Add basic Emscripten specific methods.
+	if _, err := imports.Instantiate(ctx, r); err != nil {
+		r.Close(ctx)
+		return nil, fmt.Errorf("pdfium: could not instantiate env: %w", err)
+	}
+
+	compiled, err := r.CompileModule(ctx, pdfiumWasm)
+	if err != nil {
+		r.Close(ctx)
+		return nil, fmt.Errorf("pdfium: could not compile module: %w", err)
+	}
+
+	return &Runtime{
+		runtime:  r,
+		compiled: compiled,
+		config:   config,
+	}, nil
+}
+
+// NewInstance instantiates the compiled PDFium module and initializes the
+// library in it.
+func (r *Runtime) NewInstance(ctx context.Context) (*Instance, error) {
+	// Every module in a namespace needs a unique name.
+	name := fmt.Sprintf("pdfium-%d", atomic.AddUint32(&r.instanceCounter, 1))
+
+	moduleConfig := wazero.NewModuleConfig().
+		WithName(name).
+		WithStartFunctions("_initialize").
+		WithRandSource(rand.Reader)
+
+	if r.config.Stdout != nil {
+		moduleConfig = moduleConfig.WithStdout(r.config.Stdout)
+	}
+	if r.config.Stderr != nil {
+		moduleConfig = moduleConfig.WithStderr(r.config.Stderr)
+	}
+	if r.config.FS != nil {
+		moduleConfig = moduleConfig.WithFS(r.config.FS)
+	}
+
+	mod, err := r.runtime.InstantiateModule(ctx, r.compiled, moduleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("pdfium: could not instantiate module: %w", err)
+	}
+
+	return newInstance(ctx, mod)
+}
+
+// Close closes the wazero runtime and every instance created by it.
+func (r *Runtime) Close(ctx context.Context) error {
+	return r.runtime.Close(ctx)
+}
@@ -0,0 +1,87 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"jerbob92/go-pdfium-wasm/imports"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// SaveFlags controls how a document is saved.
+type SaveFlags int
+
+const (
+	SaveFlagNone           SaveFlags = 0
+	SaveFlagIncremental    SaveFlags = 1 // FPDF_INCREMENTAL
+	SaveFlagNoIncremental  SaveFlags = 2 // FPDF_NO_INCREMENTAL
+	SaveFlagRemoveSecurity SaveFlags = 3 // FPDF_REMOVE_SECURITY
+)
+
+// errorWriter remembers the first error of the writer it wraps, PDFium only
+// learns that a block failed.
+type errorWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (w *errorWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n, err := w.writer.Write(p)
+	if err == nil && n != len(p) {
+		err = io.ErrShortWrite
+	}
+	w.err = err
+
+	return n, err
+}
+
+// Save writes a copy of the document to w. A version of 0 keeps the version
+// of the original file, otherwise it is the PDF version times ten, so 17 for
+// PDF 1.7.
+func (d *Document) Save(ctx context.Context, w io.Writer, flags SaveFlags, version int) error {
+	i := d.instance
+
+	// FPDF_FILEWRITE_Create is added to the wasm build by
+	// pdfium-binaries.patch. It allocates an FPDF_FILEWRITE whose WriteBlock
+	// is the FPDF_FILEWRITE_CB import.
+	fileWritePointer, err := i.call1(ctx, "FPDF_FILEWRITE_Create")
+	if err != nil {
+		return err
+	}
+
+	if fileWritePointer == 0 {
+		return errors.New("pdfium: could not create file write")
+	}
+	defer i.release(ctx, fileWritePointer)
+
+	writer := &errorWriter{writer: w}
+	imports.RegisterFileWriter(i.mod, uint32(fileWritePointer), writer)
+	defer imports.UnregisterFileWriter(i.mod, uint32(fileWritePointer))
+
+	var success uint64
+	if version == 0 {
+		success, err = i.call1(ctx, "FPDF_SaveAsCopy", d.handle, fileWritePointer, api.EncodeI32(int32(flags)))
+	} else {
+		success, err = i.call1(ctx, "FPDF_SaveWithVersion", d.handle, fileWritePointer, api.EncodeI32(int32(flags)), api.EncodeI32(int32(version)))
+	}
+	if err != nil {
+		return err
+	}
+
+	if writer.err != nil {
+		return fmt.Errorf("pdfium: could not save document: %w", writer.err)
+	}
+
+	if success == 0 {
+		return errors.New("pdfium: could not save document")
+	}
+
+	return nil
+}
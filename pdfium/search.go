@@ -0,0 +1,195 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// SearchOptions configures Page.Search.
+type SearchOptions struct {
+	// MatchCase makes the search case-sensitive.
+	MatchCase bool
+
+	// MatchWholeWord only matches whole words.
+	MatchWholeWord bool
+
+	// Consecutive also matches overlapping occurrences, so searching "aa" in
+	// "aaa" matches twice.
+	Consecutive bool
+
+	// StartIndex is the index of the character to start searching at.
+	StartIndex int
+}
+
+func (o SearchOptions) flags() uint64 {
+	flags := uint64(0)
+	if o.MatchCase {
+		flags |= 1 // FPDF_MATCHCASE
+	}
+	if o.MatchWholeWord {
+		flags |= 2 // FPDF_MATCHWHOLEWORD
+	}
+	if o.Consecutive {
+		flags |= 4 // FPDF_CONSECUTIVE
+	}
+
+	return flags
+}
+
+// SearchMatch is a single match of a search.
+type SearchMatch struct {
+	// CharIndex is the index of the first matched character.
+	CharIndex int
+
+	// CharCount is the number of matched characters.
+	CharCount int
+
+	// Rects cover the matched characters in page coordinates, one per line
+	// the match is on.
+	Rects []Rect
+}
+
+// SearchResults iterates over the matches of a search. It holds a text page
+// and a find handle, so Close must be called once it is no longer used, also
+// when not every match was read:
+//
+//	results, err := page.Search(ctx, "needle", pdfium.SearchOptions{})
+//	if err != nil {
+//		return err
+//	}
+//	defer results.Close(ctx)
+//
+//	for results.Next(ctx) {
+//		match := results.Match()
+//	}
+//	if err := results.Err(); err != nil {
+//		return err
+//	}
+type SearchResults struct {
+	textPage *TextPage
+	handle   uint64
+
+	match SearchMatch
+	err   error
+}
+
+// Search searches the text of the page for query.
+func (p *Page) Search(ctx context.Context, query string, options SearchOptions) (*SearchResults, error) {
+	if query == "" {
+		return nil, errors.New("pdfium: empty search query")
+	}
+
+	textPage, err := p.LoadTextPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	i := p.document.instance
+
+	// PDFium copies the query, so it can be released right away.
+	queryPointer, err := i.allocWideString(ctx, query)
+	if err != nil {
+		textPage.Close(ctx)
+		return nil, err
+	}
+	defer i.release(ctx, queryPointer)
+
+	handle, err := i.call1(ctx, "FPDFText_FindStart", textPage.handle, queryPointer, options.flags(), api.EncodeI32(int32(options.StartIndex)))
+	if err == nil && handle == 0 {
+		err = errors.New("pdfium: could not start search")
+	}
+
+	if err != nil {
+		textPage.Close(ctx)
+		return nil, err
+	}
+
+	return &SearchResults{
+		textPage: textPage,
+		handle:   handle,
+	}, nil
+}
+
+// Next advances to the next match. It returns false when there are no more
+// matches or an error occurred, see Err.
+func (r *SearchResults) Next(ctx context.Context) bool {
+	if r.handle == 0 || r.err != nil {
+		return false
+	}
+
+	i := r.textPage.page.document.instance
+
+	found, err := i.call1(ctx, "FPDFText_FindNext", r.handle)
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	if found == 0 {
+		return false
+	}
+
+	charIndex, err := i.call1(ctx, "FPDFText_GetSchResultIndex", r.handle)
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	charCount, err := i.call1(ctx, "FPDFText_GetSchCount", r.handle)
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	r.match = SearchMatch{
+		CharIndex: int(int32(charIndex)),
+		CharCount: int(int32(charCount)),
+	}
+
+	r.match.Rects, err = r.textPage.Rects(ctx, r.match.CharIndex, r.match.CharCount)
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	return true
+}
+
+// Match returns the match Next advanced to.
+func (r *SearchResults) Match() SearchMatch {
+	return r.match
+}
+
+// Err returns the error that stopped Next, if any.
+func (r *SearchResults) Err() error {
+	return r.err
+}
+
+// All reads every remaining match.
+func (r *SearchResults) All(ctx context.Context) ([]SearchMatch, error) {
+	var matches []SearchMatch
+	for r.Next(ctx) {
+		matches = append(matches, r.Match())
+	}
+
+	return matches, r.Err()
+}
+
+// Close closes the find handle and the text page. It is safe to call Close
+// more than once.
+func (r *SearchResults) Close(ctx context.Context) error {
+	if r.handle == 0 {
+		return nil
+	}
+
+	_, err := r.textPage.page.document.instance.call(ctx, "FPDFText_FindClose", r.handle)
+	r.handle = 0
+
+	if closeErr := r.textPage.Close(ctx); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	return err
+}
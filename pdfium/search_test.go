@@ -0,0 +1,26 @@
+package pdfium
+
+import "testing"
+
+func TestSearchOptionsFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		options SearchOptions
+		want    uint64
+	}{
+		{name: "none", options: SearchOptions{}, want: 0},
+		{name: "match case", options: SearchOptions{MatchCase: true}, want: 1},
+		{name: "match whole word", options: SearchOptions{MatchWholeWord: true}, want: 2},
+		{name: "consecutive", options: SearchOptions{Consecutive: true}, want: 4},
+		{name: "all", options: SearchOptions{MatchCase: true, MatchWholeWord: true, Consecutive: true}, want: 7},
+		{name: "start index", options: SearchOptions{MatchCase: true, StartIndex: 10}, want: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.options.flags(); got != test.want {
+				t.Errorf("%+v.flags() = %d, want %d", test.options, got, test.want)
+			}
+		})
+	}
+}
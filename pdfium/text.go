@@ -0,0 +1,142 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// TextPage gives access to the text of a page.
+type TextPage struct {
+	page   *Page
+	handle uint64
+}
+
+// LoadTextPage prepares the text of the page for extraction. The text page
+// must be closed before the page.
+func (p *Page) LoadTextPage(ctx context.Context) (*TextPage, error) {
+	handle, err := p.document.instance.call1(ctx, "FPDFText_LoadPage", p.handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if handle == 0 {
+		return nil, errors.New("pdfium: could not load text page")
+	}
+
+	return &TextPage{
+		page:   p,
+		handle: handle,
+	}, nil
+}
+
+// Text returns all the text of the page.
+func (p *Page) Text(ctx context.Context) (string, error) {
+	textPage, err := p.LoadTextPage(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer textPage.Close(ctx)
+
+	count, err := textPage.CountChars(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return textPage.GetText(ctx, 0, count)
+}
+
+// CountChars returns the number of characters on the page. Generated
+// characters, like the spaces and newlines PDFium inserts between words and
+// lines, are included.
+func (t *TextPage) CountChars(ctx context.Context) (int, error) {
+	count, err := t.page.document.instance.call1(ctx, "FPDFText_CountChars", t.handle)
+	if err != nil {
+		return 0, err
+	}
+
+	if int32(count) < 0 {
+		return 0, errors.New("pdfium: could not count characters")
+	}
+
+	return int(int32(count)), nil
+}
+
+// GetText returns count characters starting at the character at startIndex.
+func (t *TextPage) GetText(ctx context.Context, startIndex, count int) (string, error) {
+	if count <= 0 {
+		return "", nil
+	}
+
+	i := t.page.document.instance
+
+	// Every character is one UTF-16 code unit, plus the terminating NUL.
+	bufferSize := uint64(count+1) * 2
+	bufferPointer, err := i.alloc(ctx, bufferSize)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	written, err := i.call1(ctx, "FPDFText_GetText", t.handle, api.EncodeI32(int32(startIndex)), api.EncodeI32(int32(count)), bufferPointer)
+	if err != nil {
+		return "", err
+	}
+
+	if int32(written) <= 0 {
+		return "", nil
+	}
+
+	return i.readUTF16(ctx, bufferPointer, uint64(written)*2)
+}
+
+// GetBoundedText returns the text within the rectangle, in page coordinates.
+func (t *TextPage) GetBoundedText(ctx context.Context, left, top, right, bottom float64) (string, error) {
+	i := t.page.document.instance
+
+	params := []uint64{
+		t.handle,
+		api.EncodeF64(left),
+		api.EncodeF64(top),
+		api.EncodeF64(right),
+		api.EncodeF64(bottom),
+	}
+
+	// Without a buffer, PDFium returns the number of UTF-16 code units,
+	// excluding the terminating NUL.
+	length, err := i.call1(ctx, "FPDFText_GetBoundedText", append(params, 0, 0)...)
+	if err != nil {
+		return "", err
+	}
+
+	if int32(length) <= 0 {
+		return "", nil
+	}
+
+	bufferLength := uint64(length) + 1
+	bufferPointer, err := i.alloc(ctx, bufferLength*2)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	written, err := i.call1(ctx, "FPDFText_GetBoundedText", append(params, bufferPointer, bufferLength)...)
+	if err != nil {
+		return "", err
+	}
+
+	return i.readUTF16(ctx, bufferPointer, uint64(written)*2)
+}
+
+// Close closes the text page.
+func (t *TextPage) Close(ctx context.Context) error {
+	if t.handle == 0 {
+		return errors.New("pdfium: text page is already closed")
+	}
+
+	_, err := t.page.document.instance.call(ctx, "FPDFText_ClosePage", t.handle)
+	t.handle = 0
+
+	return err
+}
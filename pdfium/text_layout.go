@@ -0,0 +1,327 @@
+package pdfium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// TextChar is a single character of a text page.
+type TextChar struct {
+	// Index is the index of the character in the text page.
+	Index int
+
+	Rune rune
+
+	// Box is the bounding box of the glyph.
+	Box Rect
+
+	// Origin is the origin of the glyph on the baseline.
+	Origin Point
+
+	// FontSize is the font size in points.
+	FontSize float64
+
+	// FontName is the name of the font, empty when it is unknown.
+	FontName string
+
+	// FontFlags are the font descriptor flags, see section 9.8.2 of the PDF
+	// 1.7 specification.
+	FontFlags int
+
+	// Generated is true for characters PDFium inserted, like the spaces and
+	// line breaks between words and lines.
+	Generated bool
+}
+
+// TextWord is a run of characters without whitespace.
+type TextWord struct {
+	Text  string
+	Rect  Rect
+	Chars []TextChar
+}
+
+// TextLine is a line of words.
+type TextLine struct {
+	Text  string
+	Rect  Rect
+	Words []TextWord
+}
+
+// TextLayout is the text of a page grouped into lines and words.
+type TextLayout struct {
+	Lines []TextLine
+}
+
+// GetUnicode returns the character at index.
+func (t *TextPage) GetUnicode(ctx context.Context, index int) (rune, error) {
+	unicode, err := t.page.document.instance.call1(ctx, "FPDFText_GetUnicode", t.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return 0, err
+	}
+
+	return rune(uint32(unicode)), nil
+}
+
+// IsGenerated returns whether the character at index was inserted by PDFium.
+func (t *TextPage) IsGenerated(ctx context.Context, index int) (bool, error) {
+	generated, err := t.page.document.instance.call1(ctx, "FPDFText_IsGenerated", t.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return false, err
+	}
+
+	if int32(generated) < 0 {
+		return false, fmt.Errorf("pdfium: invalid character index %d", index)
+	}
+
+	return generated == 1, nil
+}
+
+// GetCharBox returns the bounding box of the character at index.
+func (t *TextPage) GetCharBox(ctx context.Context, index int) (Rect, error) {
+	i := t.page.document.instance
+
+	values, err := i.callOutFloat64s(ctx, "FPDFText_GetCharBox", 4, func(pointer uint64) []uint64 {
+		return []uint64{t.handle, api.EncodeI32(int32(index)), pointer, pointer + 8, pointer + 16, pointer + 24}
+	})
+	if err != nil {
+		return Rect{}, fmt.Errorf("pdfium: could not get box of character %d: %w", index, err)
+	}
+
+	// PDFium returns left, right, bottom, top.
+	return Rect{Left: values[0], Right: values[1], Bottom: values[2], Top: values[3]}, nil
+}
+
+// GetCharOrigin returns the origin of the character at index.
+func (t *TextPage) GetCharOrigin(ctx context.Context, index int) (Point, error) {
+	i := t.page.document.instance
+
+	values, err := i.callOutFloat64s(ctx, "FPDFText_GetCharOrigin", 2, func(pointer uint64) []uint64 {
+		return []uint64{t.handle, api.EncodeI32(int32(index)), pointer, pointer + 8}
+	})
+	if err != nil {
+		return Point{}, fmt.Errorf("pdfium: could not get origin of character %d: %w", index, err)
+	}
+
+	return Point{X: values[0], Y: values[1]}, nil
+}
+
+// GetFontSize returns the font size of the character at index in points.
+func (t *TextPage) GetFontSize(ctx context.Context, index int) (float64, error) {
+	size, err := t.page.document.instance.call1(ctx, "FPDFText_GetFontSize", t.handle, api.EncodeI32(int32(index)))
+	if err != nil {
+		return 0, err
+	}
+
+	return api.DecodeF64(size), nil
+}
+
+// GetFontInfo returns the font name and flags of the character at index.
+func (t *TextPage) GetFontInfo(ctx context.Context, index int) (string, int, error) {
+	i := t.page.document.instance
+
+	flagsPointer, err := i.alloc(ctx, 4)
+	if err != nil {
+		return "", 0, err
+	}
+	defer i.release(ctx, flagsPointer)
+
+	// Without a buffer, PDFium returns the length of the name including the
+	// terminating NUL.
+	length, err := i.call1(ctx, "FPDFText_GetFontInfo", t.handle, api.EncodeI32(int32(index)), 0, 0, flagsPointer)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if length == 0 {
+		return "", 0, nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, length)
+	if err != nil {
+		return "", 0, err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, "FPDFText_GetFontInfo", t.handle, api.EncodeI32(int32(index)), bufferPointer, length, flagsPointer); err != nil {
+		return "", 0, err
+	}
+
+	name, err := i.view(ctx, bufferPointer, length-1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	flags, err := i.readInt32s(ctx, flagsPointer, 1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return string(name), int(flags[0]), nil
+}
+
+// GetChar returns everything that is known about the character at index.
+func (t *TextPage) GetChar(ctx context.Context, index int) (TextChar, error) {
+	char := TextChar{Index: index}
+
+	var err error
+	if char.Rune, err = t.GetUnicode(ctx, index); err != nil {
+		return char, err
+	}
+
+	if char.Generated, err = t.IsGenerated(ctx, index); err != nil {
+		return char, err
+	}
+
+	// Generated characters have no glyph, so no box, origin or font.
+	if char.Generated {
+		return char, nil
+	}
+
+	if char.Box, err = t.GetCharBox(ctx, index); err != nil {
+		return char, err
+	}
+
+	if char.Origin, err = t.GetCharOrigin(ctx, index); err != nil {
+		return char, err
+	}
+
+	if char.FontSize, err = t.GetFontSize(ctx, index); err != nil {
+		return char, err
+	}
+
+	if char.FontName, char.FontFlags, err = t.GetFontInfo(ctx, index); err != nil {
+		return char, err
+	}
+
+	return char, nil
+}
+
+// Chars returns every character of the page.
+func (t *TextPage) Chars(ctx context.Context) ([]TextChar, error) {
+	count, err := t.CountChars(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chars := make([]TextChar, count)
+	for index := range chars {
+		if chars[index], err = t.GetChar(ctx, index); err != nil {
+			return nil, err
+		}
+	}
+
+	return chars, nil
+}
+
+// Rects returns the rectangles that cover count characters starting at the
+// character at startIndex. Characters on the same line that are next to each
+// other share a rectangle. A count of -1 covers every character after
+// startIndex.
+func (t *TextPage) Rects(ctx context.Context, startIndex, count int) ([]Rect, error) {
+	i := t.page.document.instance
+
+	rectCount, err := i.call1(ctx, "FPDFText_CountRects", t.handle, api.EncodeI32(int32(startIndex)), api.EncodeI32(int32(count)))
+	if err != nil {
+		return nil, err
+	}
+
+	if int32(rectCount) < 0 {
+		return nil, errors.New("pdfium: could not count rectangles")
+	}
+
+	rects := make([]Rect, int32(rectCount))
+	for index := range rects {
+		if rects[index], err = t.GetRect(ctx, index); err != nil {
+			return nil, err
+		}
+	}
+
+	return rects, nil
+}
+
+// GetRect returns the rectangle at index from the last call to Rects.
+func (t *TextPage) GetRect(ctx context.Context, index int) (Rect, error) {
+	i := t.page.document.instance
+
+	values, err := i.callOutFloat64s(ctx, "FPDFText_GetRect", 4, func(pointer uint64) []uint64 {
+		return []uint64{t.handle, api.EncodeI32(int32(index)), pointer, pointer + 8, pointer + 16, pointer + 24}
+	})
+	if err != nil {
+		return Rect{}, fmt.Errorf("pdfium: could not get rectangle %d: %w", index, err)
+	}
+
+	return Rect{Left: values[0], Top: values[1], Right: values[2], Bottom: values[3]}, nil
+}
+
+// Layout groups the characters of the page into lines and words. Lines are
+// split on line breaks and words on whitespace, the whitespace itself is not
+// part of any word.
+func (t *TextPage) Layout(ctx context.Context) (*TextLayout, error) {
+	chars, err := t.Chars(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return layoutChars(chars), nil
+}
+
+// layoutChars groups chars into lines and words, like Layout.
+func layoutChars(chars []TextChar) *TextLayout {
+	layout := &TextLayout{}
+	line := TextLine{}
+	word := TextWord{}
+
+	endWord := func() {
+		if len(word.Chars) == 0 {
+			return
+		}
+
+		if len(line.Words) == 0 {
+			line.Rect = word.Rect
+		} else {
+			line.Rect = line.Rect.Union(word.Rect)
+		}
+		line.Words = append(line.Words, word)
+		word = TextWord{}
+	}
+
+	endLine := func() {
+		endWord()
+		if len(line.Words) == 0 {
+			return
+		}
+
+		words := make([]string, len(line.Words))
+		for n := range line.Words {
+			words[n] = line.Words[n].Text
+		}
+		line.Text = strings.Join(words, " ")
+
+		layout.Lines = append(layout.Lines, line)
+		line = TextLine{}
+	}
+
+	for _, char := range chars {
+		switch char.Rune {
+		case '\r', '\n':
+			endLine()
+		case ' ', '\t', 0:
+			endWord()
+		default:
+			if len(word.Chars) == 0 {
+				word.Rect = char.Box
+			} else {
+				word.Rect = word.Rect.Union(char.Box)
+			}
+			word.Chars = append(word.Chars, char)
+			word.Text += string(char.Rune)
+		}
+	}
+	endLine()
+
+	return layout
+}
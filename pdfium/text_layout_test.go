@@ -0,0 +1,90 @@
+package pdfium
+
+import (
+	"reflect"
+	"testing"
+)
+
+// layoutText returns the text of the words of every line of layout.
+func layoutText(layout *TextLayout) [][]string {
+	lines := [][]string{}
+	for _, line := range layout.Lines {
+		words := []string{}
+		for _, word := range line.Words {
+			words = append(words, word.Text)
+		}
+		lines = append(lines, words)
+	}
+	return lines
+}
+
+// textChars returns a character for every rune of s, each one point wide
+// and placed after the previous one.
+func textChars(s string) []TextChar {
+	var chars []TextChar
+	for index, r := range []rune(s) {
+		x := float64(index)
+		chars = append(chars, TextChar{
+			Index: index,
+			Rune:  r,
+			Box:   Rect{Left: x, Top: 10, Right: x + 1, Bottom: 0},
+		})
+	}
+	return chars
+}
+
+func TestLayoutChars(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want [][]string
+	}{
+		{name: "empty", text: "", want: [][]string{}},
+		{name: "word", text: "hello", want: [][]string{{"hello"}}},
+		{name: "words", text: "hello world", want: [][]string{{"hello", "world"}}},
+		{name: "lines", text: "hello\r\nworld", want: [][]string{{"hello"}, {"world"}}},
+		{name: "tabs and NUL", text: "a\tb\x00c", want: [][]string{{"a", "b", "c"}}},
+		{name: "repeated whitespace", text: "  a  b  ", want: [][]string{{"a", "b"}}},
+		{name: "empty lines", text: "\n\na\n\n\nb\n", want: [][]string{{"a"}, {"b"}}},
+		{name: "whitespace only", text: " \t\r\n", want: [][]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := layoutText(layoutChars(textChars(test.text)))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("layoutChars(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLayoutCharsRects(t *testing.T) {
+	layout := layoutChars(textChars("ab cd\nef"))
+
+	if len(layout.Lines) != 2 {
+		t.Fatalf("layoutChars() returned %d lines, want 2", len(layout.Lines))
+	}
+
+	first := layout.Lines[0]
+	if first.Text != "ab cd" {
+		t.Errorf("first line text = %q, want %q", first.Text, "ab cd")
+	}
+
+	if want := (Rect{Left: 0, Top: 10, Right: 5, Bottom: 0}); first.Rect != want {
+		t.Errorf("first line rect = %v, want %v", first.Rect, want)
+	}
+
+	if want := (Rect{Left: 3, Top: 10, Right: 5, Bottom: 0}); first.Words[1].Rect != want {
+		t.Errorf("second word rect = %v, want %v", first.Words[1].Rect, want)
+	}
+
+	word := first.Words[1]
+	if len(word.Chars) != 2 || word.Chars[0].Index != 3 || word.Chars[1].Index != 4 {
+		t.Errorf("second word chars = %v, want the characters at 3 and 4", word.Chars)
+	}
+
+	if want := (Rect{Left: 6, Top: 10, Right: 8, Bottom: 0}); layout.Lines[1].Rect != want {
+		t.Errorf("second line rect = %v, want %v", layout.Lines[1].Rect, want)
+	}
+}
@@ -0,0 +1,77 @@
+package pdfium
+
+import (
+	"context"
+	"encoding/binary"
+	"unicode/utf16"
+)
+
+// decodeUTF16 decodes UTF-16LE data as PDFium writes it, up to the first NUL
+// character.
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for p := 0; p+1 < len(data); p += 2 {
+		unit := binary.LittleEndian.Uint16(data[p:])
+		if unit == 0 {
+			break
+		}
+		units = append(units, unit)
+	}
+
+	return string(utf16.Decode(units))
+}
+
+// encodeUTF16 encodes s as NUL terminated UTF-16LE, the FPDF_WIDESTRING
+// format.
+func encodeUTF16(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data := make([]byte, (len(units)+1)*2)
+	for p, unit := range units {
+		binary.LittleEndian.PutUint16(data[p*2:], unit)
+	}
+
+	return data
+}
+
+// allocWideString copies s into newly allocated linear memory as an
+// FPDF_WIDESTRING.
+func (i *Instance) allocWideString(ctx context.Context, s string) (uint64, error) {
+	return i.allocBytes(ctx, encodeUTF16(s))
+}
+
+// readUTF16 reads size bytes of UTF-16LE at pointer and decodes them.
+func (i *Instance) readUTF16(ctx context.Context, pointer uint64, size uint64) (string, error) {
+	data, err := i.view(ctx, pointer, size)
+	if err != nil {
+		return "", err
+	}
+
+	return decodeUTF16(data), nil
+}
+
+// callUTF16 calls the PDFium function with the given name, that writes a
+// UTF-16LE string to a buffer given as its last two parameters. It is first
+// called without a buffer to get the length in bytes, including the
+// terminating NUL.
+func (i *Instance) callUTF16(ctx context.Context, name string, params ...uint64) (string, error) {
+	length, err := i.call1(ctx, name, append(params[:len(params):len(params)], 0, 0)...)
+	if err != nil {
+		return "", err
+	}
+
+	if uint32(length) <= 2 {
+		return "", nil
+	}
+
+	bufferPointer, err := i.alloc(ctx, length)
+	if err != nil {
+		return "", err
+	}
+	defer i.release(ctx, bufferPointer)
+
+	if _, err := i.call1(ctx, name, append(params[:len(params):len(params)], bufferPointer, length)...); err != nil {
+		return "", err
+	}
+
+	return i.readUTF16(ctx, bufferPointer, length)
+}
@@ -0,0 +1,101 @@
+package pdfium
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestDecodeUTF16(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "empty", data: nil, want: ""},
+		{name: "only NUL", data: []byte{0, 0}, want: ""},
+		{name: "ascii", data: []byte{'a', 0, 'b', 0, 'c', 0, 0, 0}, want: "abc"},
+		{name: "without NUL", data: []byte{'a', 0, 'b', 0}, want: "ab"},
+		{name: "stops at NUL", data: []byte{'a', 0, 0, 0, 'b', 0}, want: "a"},
+		{name: "odd length", data: []byte{'a', 0, 'b'}, want: "a"},
+		{name: "latin", data: []byte{'h', 0, 0xe9, 0, 0, 0}, want: "hé"},
+		{name: "surrogate pair", data: []byte{0x34, 0xd8, 0x1e, 0xdd, 0, 0}, want: "𝄞"},
+		{name: "lone surrogate", data: []byte{0x34, 0xd8, 0, 0}, want: "�"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := decodeUTF16(test.data); got != test.want {
+				t.Errorf("decodeUTF16(%v) = %q, want %q", test.data, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEncodeUTF16(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []byte
+	}{
+		{s: "", want: []byte{0, 0}},
+		{s: "ab", want: []byte{'a', 0, 'b', 0, 0, 0}},
+		{s: "hé", want: []byte{'h', 0, 0xe9, 0, 0, 0}},
+		{s: "€", want: []byte{0xac, 0x20, 0, 0}},
+		{s: "𝄞", want: []byte{0x34, 0xd8, 0x1e, 0xdd, 0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			got := encodeUTF16(test.s)
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("encodeUTF16(%q) = %v, want %v", test.s, got, test.want)
+			}
+
+			if decoded := decodeUTF16(got); decoded != test.s {
+				t.Errorf("decodeUTF16(encodeUTF16(%q)) = %q", test.s, decoded)
+			}
+		})
+	}
+}
+
+func TestAllocWideString(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, nil)
+
+	pointer, err := i.allocWideString(ctx, "hé𝄞")
+	if err != nil {
+		t.Fatalf("allocWideString() error: %v", err)
+	}
+
+	if got, err := i.readUTF16(ctx, pointer, 10); err != nil || got != "hé𝄞" {
+		t.Errorf("readUTF16() = %q, %v, want %q", got, err, "hé𝄞")
+	}
+}
+
+func TestCallUTF16(t *testing.T) {
+	ctx := context.Background()
+	i := newTestInstance(t, map[string]testFunction{
+		// Writes "hi" to the buffer when there is one and always returns the
+		// length of "hi" with the terminating NUL in bytes.
+		"FPDF_GetText": {
+			params:  i32s(3),
+			results: i32s(1),
+			body: join(
+				localGet(1), []byte{opIf, 0x40},
+				localGet(1), i32Const('h'|'i'<<16), memarg(opI32Store, 2, 0),
+				localGet(1), i32Const(0), memarg(opI32Store16, 1, 4),
+				[]byte{opEnd},
+				i32Const(6),
+			),
+		},
+		"FPDF_GetEmptyText": returnI32(i32s(3), 2),
+	})
+
+	if got, err := i.callUTF16(ctx, "FPDF_GetText", 100); err != nil || got != "hi" {
+		t.Errorf("callUTF16(FPDF_GetText) = %q, %v, want %q", got, err, "hi")
+	}
+
+	if got, err := i.callUTF16(ctx, "FPDF_GetEmptyText", 100); err != nil || got != "" {
+		t.Errorf("callUTF16(FPDF_GetEmptyText) = %q, %v, want an empty string", got, err)
+	}
+}